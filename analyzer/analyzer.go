@@ -0,0 +1,286 @@
+/*
+Package analyzer provides a static checker for the struct tags read by [github.com/willoma/defaults].
+
+It reports, at compile time instead of when defaults are applied:
+
+  - default values that cannot be parsed into the static type of their field
+    (including profile variants, JSON defaults and conditional defaults),
+    and invalid validation tags next to them,
+  - default tags on unexported fields, which are silently ignored,
+  - default tags on fields of unsupported kinds, like functions, interfaces or uintptrs.
+
+Default values of named types that implement [encoding.TextUnmarshaler] outside of the standard library
+are not parsed, since their parser is only known at runtime.
+
+Parsers registered with [defaults.RegisterParser] or [defaults.WithParser] are not known either:
+the default values of other named types are parsed like values of their underlying type,
+which reports false positives for types with a custom parser. Such types are skipped
+when listed in the "skip-types" flag, as comma-separated qualified names like "example.com/config.Cents",
+and single fields are skipped when their comment contains the "//defaults:ignore" directive:
+
+	Price Cents `default:"1.50"` //defaults:ignore
+
+The checker only depends on the standard library [go/ast] and [go/types] packages.
+Its API mirrors the one of golang.org/x/tools/go/analysis, so that it can be wrapped
+into an analysis.Analyzer and run by go vet:
+
+	var Analyzer = &analysis.Analyzer{
+		Name: analyzer.Default.Name,
+		Doc:  analyzer.Default.Doc,
+		Run: func(pass *analysis.Pass) (any, error) {
+			return analyzer.Default.Run(&analyzer.Pass{
+				Fset:      pass.Fset,
+				Files:     pass.Files,
+				TypesInfo: pass.TypesInfo,
+				Report: func(diag analyzer.Diagnostic) {
+					pass.Reportf(diag.Pos, "%s", diag.Message)
+				},
+			})
+		},
+	}
+*/
+package analyzer
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/defaults"
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// Analyzer describes a static checker, like analysis.Analyzer in golang.org/x/tools/go/analysis.
+type Analyzer struct {
+	Name  string
+	Doc   string
+	Flags flag.FlagSet
+	Run   func(pass *Pass) (any, error)
+}
+
+// Pass provides the analyzed package to the checker, like analysis.Pass in golang.org/x/tools/go/analysis.
+type Pass struct {
+	Fset      *token.FileSet
+	Files     []*ast.File
+	TypesInfo *types.Info
+	Report    func(Diagnostic)
+}
+
+// Diagnostic is a problem found by the checker.
+type Diagnostic struct {
+	Pos     token.Pos
+	Message string
+}
+
+// Default is the checker for the "default" struct tags.
+// The "tags" flag configures a comma-separated list of tag names, like [defaults.WithTagNames],
+// and the "skip-types" flag a comma-separated list of qualified type names whose default values are not checked.
+//
+//nolint:gochecknoglobals // The checker is exposed as a variable, like analysis analyzers.
+var Default = newDefault()
+
+func newDefault() *Analyzer {
+	analyzer := &Analyzer{
+		Name: "defaults",
+		Doc:  "check the struct tags read by github.com/willoma/defaults",
+	}
+
+	tagNames := analyzer.Flags.String("tags", "default", "comma-separated list of default tag names")
+	skipTypes := analyzer.Flags.String("skip-types", "", "comma-separated list of qualified names of types with custom parsers")
+
+	analyzer.Run = func(pass *Pass) (any, error) {
+		skip := map[string]bool{}
+
+		for _, name := range strings.Split(*skipTypes, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				skip[name] = true
+			}
+		}
+
+		run(pass, strings.Split(*tagNames, ","), skip)
+
+		return nil, nil //nolint:nilnil // There is no result, like most analyzers.
+	}
+
+	return analyzer
+}
+
+func run(pass *Pass, tagNames []string, skipTypes map[string]bool) {
+	checker := &checker{
+		pass:      pass,
+		tagNames:  tagNames,
+		skipTypes: skipTypes,
+		parser:    defaults.NewParser(defaults.WithTagNames(tagNames...)),
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if structType, ok := node.(*ast.StructType); ok {
+				checker.checkStruct(structType)
+			}
+
+			return true
+		})
+	}
+}
+
+type checker struct {
+	pass      *Pass
+	tagNames  []string
+	skipTypes map[string]bool
+	parser    *defaults.Parser
+}
+
+// ignoreDirective is the comment directive that disables the checks of a field.
+const ignoreDirective = "//defaults:ignore"
+
+func (c *checker) checkStruct(structType *ast.StructType) {
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		rawTag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		tag := reflect.StructTag(rawTag)
+		if !c.hasDefaultTag(tag) || isIgnored(field) {
+			continue
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		if len(field.Names) == 0 {
+			names = append(names, tags.EmbeddedName(field.Type))
+		}
+
+		for _, name := range names {
+			c.checkField(field, name, tag)
+		}
+	}
+}
+
+func (c *checker) checkField(field *ast.Field, name string, tag reflect.StructTag) {
+	if !token.IsExported(name) {
+		c.report(field.Tag.Pos(), "default tag on unexported field %s is ignored", name)
+
+		return
+	}
+
+	typ := c.pass.TypesInfo.TypeOf(field.Type)
+	if typ == nil {
+		return
+	}
+
+	if kind, unsupported := unsupportedKind(typ, map[types.Type]bool{}); unsupported {
+		c.report(field.Tag.Pos(), "default tag on field %s of unsupported kind %s", name, kind)
+
+		return
+	}
+
+	reflectType, ok := newConverter(c.skipTypes).convert(typ)
+	if !ok {
+		return
+	}
+
+	structType := reflect.StructOf([]reflect.StructField{{Name: "F", Type: reflectType, Tag: tag}})
+
+	err := c.parser.CheckType(structType)
+	if err == nil {
+		return
+	}
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		line = strings.TrimPrefix(line, "F: ")
+		line = strings.TrimPrefix(line, "F.")
+		c.report(field.Tag.Pos(), "invalid default tag on field %s: %s", name, line)
+	}
+}
+
+// hasDefaultTag reports whether tag contains one of the tags that define default values.
+func (c *checker) hasDefaultTag(tag reflect.StructTag) bool {
+	for _, key := range tags.Keys(tag) {
+		switch key {
+		case tags.JSON, tags.Conditional, tags.PointerMode:
+			return true
+		}
+
+		if tags.IsDefault(key, c.tagNames) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIgnored reports whether the doc comment or the line comment of field contains the ignore directive.
+func isIgnored(field *ast.Field) bool {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if group == nil {
+			continue
+		}
+
+		for _, comment := range group.List {
+			if comment.Text == ignoreDirective || strings.HasPrefix(comment.Text, ignoreDirective+" ") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *checker) report(pos token.Pos, format string, args ...any) {
+	c.pass.Report(Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// unsupportedKind reports whether typ (or the type of its elements) is a kind that cannot have defaults.
+func unsupportedKind(typ types.Type, visited map[types.Type]bool) (string, bool) {
+	if visited[typ] {
+		return "", false
+	}
+
+	visited[typ] = true
+
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		switch typ.Kind() {
+		case types.Uintptr:
+			return "uintptr", true
+		case types.UnsafePointer:
+			return "unsafe.Pointer", true
+		}
+	case *types.Signature:
+		return "func", true
+	case *types.Interface:
+		return "interface", true
+	case *types.Chan:
+		if typ.Dir() != types.SendRecv {
+			return "unidirectional chan", true
+		}
+	case *types.Pointer:
+		return unsupportedKind(typ.Elem(), visited)
+	case *types.Slice:
+		return unsupportedKind(typ.Elem(), visited)
+	case *types.Array:
+		return unsupportedKind(typ.Elem(), visited)
+	case *types.Map:
+		if kind, unsupported := unsupportedKind(typ.Key(), visited); unsupported {
+			return kind, true
+		}
+
+		return unsupportedKind(typ.Elem(), visited)
+	}
+
+	return "", false
+}
@@ -0,0 +1,105 @@
+package analyzer_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/willoma/defaults/analyzer"
+)
+
+const source = `package config
+
+import "time"
+
+// Cents and Percent have custom parsers, registered at runtime:
+// only Percent is skipped, fields of type Cents must be ignored one by one.
+type Cents int
+
+type Percent float64
+
+type Backend struct {
+	Port int ` + "`default:\"eighty\"`" + `
+}
+
+type Config struct {
+	Name     string        ` + "`default:\"app\"`" + `
+	Timeout  time.Duration ` + "`default:\"5s\" default.prod:\"forever\"`" + `
+	Count    int           ` + "`default:\"abc\"`" + `
+	Ratio    float64       ` + "`default:\"0.5\" min:\"zero\"`" + `
+	Backends []Backend     ` + "`default:\"[{Port:80}]\"`" + `
+	Points   []struct{ X, Y int } ` + "`default:\"[{X:1,Y:two}]\"`" + `
+	Handler  func()        ` + "`default:\"x\"`" + `
+	Address  uintptr       ` + "`default:\"0\"`" + `
+	URL      string        ` + "`default:\"http://${Name}\"`" + `
+	JSON     []int         ` + "`default_json:\"[1,\\\"two\\\"]\"`" + `
+	host     string        ` + "`default:\"localhost\"`" + `
+	Price    Cents         ` + "`default:\"1.50\"`" + ` //defaults:ignore
+	Discount Percent       ` + "`default:\"10%\"`" + `
+	Prices   []Cents       ` + "`default:\"1.50\"`" + `
+}
+`
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "config.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("config", fset, []*ast.File{file}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := analyzer.Default.Flags.Set("skip-types", "config.Percent"); err != nil {
+		t.Fatal(err)
+	}
+
+	var diagnostics []string
+
+	_, err = analyzer.Default.Run(&analyzer.Pass{
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		TypesInfo: info,
+		Report: func(diag analyzer.Diagnostic) {
+			diagnostics = append(diagnostics, diag.Message)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPrefixes := []string{
+		"invalid default tag on field Port: ",
+		"invalid default tag on field Timeout: ",
+		"invalid default tag on field Count: ",
+		"invalid default tag on field Ratio: ",
+		"invalid default tag on field Points: 0.Y: ",
+		"default tag on field Handler of unsupported kind func",
+		"default tag on field Address of unsupported kind uintptr",
+		"invalid default tag on field JSON: 1: ",
+		"default tag on unexported field host is ignored",
+		"invalid default tag on field Prices: ",
+	}
+
+	for _, prefix := range expectedPrefixes {
+		if !slices.ContainsFunc(diagnostics, func(diag string) bool { return strings.HasPrefix(diag, prefix) }) {
+			t.Errorf("missing diagnostic %q", prefix)
+		}
+	}
+
+	if len(diagnostics) != len(expectedPrefixes) {
+		t.Errorf("expected %d diagnostics, got %d:\n%s", len(expectedPrefixes), len(diagnostics), strings.Join(diagnostics, "\n"))
+	}
+}
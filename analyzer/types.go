@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"go/types"
+	"io/fs"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// knownTypes maps the standard library types that have specific parsers
+// or implement [encoding.TextUnmarshaler] to their runtime type.
+//
+//nolint:gochecknoglobals // This is a lookup table.
+var knownTypes = map[string]reflect.Type{
+	"io/fs.FileMode":     reflect.TypeFor[fs.FileMode](),
+	"log/slog.Level":     reflect.TypeFor[slog.Level](),
+	"math/big.Float":     reflect.TypeFor[big.Float](),
+	"math/big.Int":       reflect.TypeFor[big.Int](),
+	"math/big.Rat":       reflect.TypeFor[big.Rat](),
+	"net.HardwareAddr":   reflect.TypeFor[net.HardwareAddr](),
+	"net.IP":             reflect.TypeFor[net.IP](),
+	"net/netip.Addr":     reflect.TypeFor[netip.Addr](),
+	"net/netip.AddrPort": reflect.TypeFor[netip.AddrPort](),
+	"net/netip.Prefix":   reflect.TypeFor[netip.Prefix](),
+	"regexp.Regexp":      reflect.TypeFor[regexp.Regexp](),
+	"time.Duration":      reflect.TypeFor[time.Duration](),
+	"time.Time":          reflect.TypeFor[time.Time](),
+}
+
+//nolint:gochecknoglobals // This is a lookup table.
+var basicTypes = map[types.BasicKind]reflect.Type{
+	types.Bool:       reflect.TypeFor[bool](),
+	types.Int:        reflect.TypeFor[int](),
+	types.Int8:       reflect.TypeFor[int8](),
+	types.Int16:      reflect.TypeFor[int16](),
+	types.Int32:      reflect.TypeFor[int32](),
+	types.Int64:      reflect.TypeFor[int64](),
+	types.Uint:       reflect.TypeFor[uint](),
+	types.Uint8:      reflect.TypeFor[uint8](),
+	types.Uint16:     reflect.TypeFor[uint16](),
+	types.Uint32:     reflect.TypeFor[uint32](),
+	types.Uint64:     reflect.TypeFor[uint64](),
+	types.Float32:    reflect.TypeFor[float32](),
+	types.Float64:    reflect.TypeFor[float64](),
+	types.Complex64:  reflect.TypeFor[complex64](),
+	types.Complex128: reflect.TypeFor[complex128](),
+	types.String:     reflect.TypeFor[string](),
+}
+
+// converter converts static types to equivalent runtime types,
+// so that default values can be parsed with the runtime parsers.
+type converter struct {
+	visiting map[*types.Named]bool
+
+	// skip holds the qualified names of the types that must not be converted,
+	// because they have custom parsers.
+	skip map[string]bool
+}
+
+func newConverter(skip map[string]bool) *converter {
+	return &converter{visiting: map[*types.Named]bool{}, skip: skip}
+}
+
+// convert returns the runtime type equivalent to typ,
+// and reports whether typ can be converted. Named types that implement
+// [encoding.TextUnmarshaler] outside of the standard library, and skipped types, cannot be converted.
+func (c *converter) convert(typ types.Type) (reflect.Type, bool) {
+	switch typ := typ.(type) {
+	case *types.Alias:
+		return c.convert(types.Unalias(typ))
+
+	case *types.Named:
+		return c.convertNamed(typ)
+
+	case *types.Basic:
+		result, ok := basicTypes[typ.Kind()]
+
+		return result, ok
+
+	case *types.Pointer:
+		return c.convertElem(typ.Elem(), reflect.PointerTo)
+
+	case *types.Slice:
+		return c.convertElem(typ.Elem(), reflect.SliceOf)
+
+	case *types.Array:
+		return c.convertElem(typ.Elem(), func(elem reflect.Type) reflect.Type {
+			return reflect.ArrayOf(int(typ.Len()), elem)
+		})
+
+	case *types.Chan:
+		return c.convertElem(typ.Elem(), func(elem reflect.Type) reflect.Type {
+			return reflect.ChanOf(reflect.BothDir, elem)
+		})
+
+	case *types.Map:
+		key, ok := c.convert(typ.Key())
+		if !ok || !key.Comparable() {
+			return nil, false
+		}
+
+		return c.convertElem(typ.Elem(), func(elem reflect.Type) reflect.Type {
+			return reflect.MapOf(key, elem)
+		})
+
+	case *types.Struct:
+		return c.convertStruct(typ)
+
+	default:
+		return nil, false
+	}
+}
+
+func (c *converter) convertNamed(typ *types.Named) (reflect.Type, bool) {
+	if pkg := typ.Obj().Pkg(); pkg != nil {
+		name := pkg.Path() + "." + typ.Obj().Name()
+
+		if known, ok := knownTypes[name]; ok {
+			return known, true
+		}
+
+		if c.skip[name] {
+			return nil, false
+		}
+	}
+
+	if method, _, _ := types.LookupFieldOrMethod(types.NewPointer(typ), false, nil, "UnmarshalText"); method != nil {
+		return nil, false
+	}
+
+	if c.visiting[typ] {
+		return nil, false
+	}
+
+	c.visiting[typ] = true
+	defer delete(c.visiting, typ)
+
+	return c.convert(typ.Underlying())
+}
+
+func (c *converter) convertElem(elem types.Type, build func(reflect.Type) reflect.Type) (reflect.Type, bool) {
+	converted, ok := c.convert(elem)
+	if !ok {
+		return nil, false
+	}
+
+	return build(converted), true
+}
+
+// convertStruct converts the exported fields of a struct type, without their tags,
+// which are checked where the struct is declared.
+func (c *converter) convertStruct(typ *types.Struct) (reflect.Type, bool) {
+	fields := make([]reflect.StructField, 0, typ.NumFields())
+
+	for i := range typ.NumFields() {
+		field := typ.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		if field.Embedded() {
+			return nil, false
+		}
+
+		converted, ok := c.convert(field.Type())
+		if !ok {
+			return nil, false
+		}
+
+		fields = append(fields, reflect.StructField{Name: field.Name(), Type: converted})
+	}
+
+	return reflect.StructOf(fields), true
+}
@@ -0,0 +1,255 @@
+package defaults
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// Check checks all the default, conditional and validation tags of type T,
+// which must be a struct type or a pointer to a struct type, without needing a value.
+// See [CheckType] for details.
+func Check[T any]() error { return CheckType(reflect.TypeFor[T]()) }
+
+// CheckType checks all the default, conditional and validation tags of typ,
+// which must be a struct type or a pointer to a struct type, without needing a value.
+//
+// Every default value (including those of all profiles) is parsed into a scratch value,
+// and the tags of nested structs, pointers to structs and collections of structs are checked too.
+// All errors are returned, joined, prefixed with the path of the field,
+// with "*" designating the elements of slices, arrays and maps.
+// Each struct type is checked once, errors being reported with the path of its first occurrence.
+//
+// Default values computed by providers or containing references are not parsed,
+// because they are only known when defaults are applied, but unknown providers are reported.
+//
+// It is meant to be called from a unit test or from an init function for every configuration type,
+// so that invalid tags are caught before defaults are applied.
+func CheckType(typ reflect.Type) error { return defaultParser.CheckType(typ) }
+
+// CheckType checks all the tags of typ, see [CheckType] for details.
+func (p *Parser) CheckType(typ reflect.Type) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return ErrMustBePointerToAStruct
+	}
+
+	app := &applier{Parser: p, checking: true}
+
+	return errors.Join(app.checkStruct(typ, map[reflect.Type]bool{})...)
+}
+
+func (a *applier) checkStruct(typ reflect.Type, visited map[reflect.Type]bool) []error {
+	if visited[typ] {
+		return nil
+	}
+
+	visited[typ] = true
+
+	var errs []error
+
+	for i := range typ.NumField() {
+		typeField := typ.Field(i)
+		if !typeField.IsExported() {
+			continue
+		}
+
+		if fieldErrs := a.checkField(typ, typeField, visited); len(fieldErrs) > 0 {
+			errs = append(errs, addErrorsPrefixes(typeField.Name, fieldErrs)...)
+		}
+	}
+
+	return errs
+}
+
+func (a *applier) checkField(parent reflect.Type, typeField reflect.StructField, visited map[reflect.Type]bool) []error {
+	var errs []error
+
+	for _, key := range tags.Keys(typeField.Tag) {
+		if tags.IsDefault(key, a.tagNames) {
+			value, _ := typeField.Tag.Lookup(key)
+			errs = append(errs, a.checkDefault(typeField.Type, value)...)
+		}
+	}
+
+	if value, ok := typeField.Tag.Lookup(jsonTagName); ok {
+		_, jsonErrs := a.parseJSON(reflect.New(typeField.Type).Elem(), value)
+		errs = append(errs, jsonErrs...)
+	}
+
+	if _, err := a.fieldPointerMode(typeField); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, a.checkConditions(parent, typeField)...)
+	errs = append(errs, a.checkValidationTags(typeField)...)
+
+	// Check the nested structs, "*" designating collection elements.
+	nested := typeField.Type
+	prefixes := []string{}
+
+	for {
+		switch nested.Kind() {
+		case reflect.Pointer:
+			nested = nested.Elem()
+
+			continue
+		case reflect.Array, reflect.Map, reflect.Slice:
+			nested = nested.Elem()
+			prefixes = append(prefixes, "*")
+
+			continue
+		}
+
+		break
+	}
+
+	if nested.Kind() == reflect.Struct && !a.hasParser(nested) {
+		nestedErrs := a.checkStruct(nested, visited)
+		for i := len(prefixes) - 1; i >= 0 && len(nestedErrs) > 0; i-- {
+			nestedErrs = addErrorsPrefixes(prefixes[i], nestedErrs)
+		}
+
+		errs = append(errs, nestedErrs...)
+	}
+
+	return errs
+}
+
+// checkDefault parses value into a scratch value of type typ, unless it is dynamic.
+func (a *applier) checkDefault(typ reflect.Type, value string) []error {
+	if name, _, ok := tags.Provider(value); ok {
+		a.mu.RLock()
+		_, ok := a.providers[name]
+		a.mu.RUnlock()
+
+		if !ok {
+			return []error{fmt.Errorf("%w: %q", ErrUnknownProvider, name)}
+		}
+
+		return nil
+	}
+
+	if tags.IsDynamic(value) {
+		return nil
+	}
+
+	value, err := a.interpolate(tags.Unescape(value))
+	if err != nil {
+		return []error{err}
+	}
+
+	_, errs := a.parse(reflect.New(typ).Elem(), value, true, true)
+
+	return errs
+}
+
+// checkConditions checks the syntax of the "default_if" rules of typeField, their default values,
+// and their expected values when they refer to fields of parent.
+func (a *applier) checkConditions(parent reflect.Type, typeField reflect.StructField) []error {
+	tag, ok := typeField.Tag.Lookup(conditionalTagName)
+	if !ok {
+		return nil
+	}
+
+	conditions, err := parseConditions(tag)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+
+	for _, cond := range conditions {
+		errs = append(errs, a.checkDefault(typeField.Type, cond.result)...)
+
+		// Conditions on fields of ancestors can only be checked when applying defaults.
+		if referenced, ok := staticField(parent, cond.path); ok {
+			_, condErrs := a.parse(reflect.New(referenced).Elem(), cond.value, true, true)
+			errs = append(errs, condErrs...)
+		}
+	}
+
+	return errs
+}
+
+// checkValidationTags checks the syntax of the validation tags of typeField.
+func (a *applier) checkValidationTags(typeField reflect.StructField) []error {
+	var errs []error
+
+	typ := typeField.Type
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	scratch := reflect.New(typ).Elem()
+	_, hasLength := lengthOf(scratch)
+
+	for _, name := range []string{"min", "max"} {
+		if value, ok := typeField.Tag.Lookup(name); ok {
+			if _, _, err := a.compareBound(scratch, value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if value, ok := typeField.Tag.Lookup("len"); ok {
+		if _, err := strconv.Atoi(value); err != nil {
+			errs = append(errs, err)
+		} else if !hasLength {
+			errs = append(errs, fmt.Errorf("%w: len needs values with a length", ErrUnsupportedType))
+		}
+	}
+
+	if value, ok := typeField.Tag.Lookup("oneof"); ok {
+		for _, candidate := range asList(value) {
+			_, oneOfErrs := a.parse(reflect.New(typ).Elem(), candidate, true, true)
+			errs = append(errs, oneOfErrs...)
+		}
+	}
+
+	if value, ok := typeField.Tag.Lookup("pattern"); ok {
+		if _, err := regexp.Compile(value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, name := range []string{"nonzero", "required"} {
+		if value, ok := typeField.Tag.Lookup(name); ok {
+			if _, err := parseFlag(value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// staticField returns the type of the field of typ at path, if path designates a field of typ.
+func staticField(typ reflect.Type, path string) (reflect.Type, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		typeField, ok := typ.FieldByName(segment)
+		if !ok || !typeField.IsExported() {
+			return nil, false
+		}
+
+		typ = typeField.Type
+	}
+
+	return typ, true
+}
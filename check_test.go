@@ -0,0 +1,77 @@
+package defaults_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type checkedBackend struct {
+	Port    int           `default:"eighty"`
+	Timeout time.Duration `default:"5s"     default.prod:"forever"`
+}
+
+type checkedConfig struct {
+	Name      string           `default:"app"`
+	Count     int              `default:"abc"`
+	Backends  []checkedBackend `default:"[{Port:80}]"`
+	Named     map[string]*checkedBackend
+	Host      string          `default:"@nohost"`
+	URL       string          `default:"http://${Name}"`
+	TLS       bool            `default:"false"`
+	TLSPort   int             `default_if:"TLS=maybe:443;TLS=true:https"`
+	Ratio     float64         `min:"zero"`
+	Handler   func()          `default:"x"`
+	Pattern   string          `pattern:"["`
+	JSON      []int           `default_json:"[1,\"two\"]"`
+	Pointer   *checkedBackend `default_ptr:"sometimes"`
+	Required  string          `required:"maybe"`
+	Recursive *checkedConfig
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	if err := defaults.Check[unmarshaltarget](); err != nil {
+		t.Errorf("unexpected error for a valid type: %s", err)
+	}
+
+	err := defaults.Check[*checkedConfig]()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+
+	expectedPrefixes := []string{
+		"Count: ",
+		"Backends.*.Port: ",
+		"Backends.*.Timeout: ",
+		"Host: unknown default value provider",
+		"TLSPort: strconv.ParseBool",
+		"TLSPort: strconv.Atoi",
+		"Ratio: ",
+		"Handler: unsupported type",
+		"Pattern: ",
+		"JSON.1: ",
+		"Pointer: invalid format",
+		"Required: ",
+	}
+
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != len(expectedPrefixes) {
+		t.Fatalf("wrong number of errors (%d, expected %d):\n%s", len(lines), len(expectedPrefixes), err)
+	}
+
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expectedPrefixes[i]) {
+			t.Errorf("wrong error %d: %q, expected prefix %q", i, line, expectedPrefixes[i])
+		}
+	}
+
+	if err := defaults.CheckType(reflect.TypeFor[int]()); !errors.Is(err, defaults.ErrMustBePointerToAStruct) {
+		t.Errorf("expected ErrMustBePointerToAStruct, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package defaults
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithClock configures the function used to get the current time,
+// against which relative [time.Time] defaults like "now" or "tomorrow" are resolved,
+// instead of [time.Now]. It is mostly useful in tests, to get deterministic values.
+func WithClock(now func() time.Time) Option {
+	return func(p *Parser) {
+		p.clock = now
+	}
+}
+
+// now returns the current time, which is the same for all fields during a single application of defaults.
+func (a *applier) now() time.Time {
+	if a.currentTime.IsZero() {
+		a.currentTime = a.clock()
+	}
+
+	return a.currentTime
+}
+
+// parseRelativeTime parses a time relative to the current time, in the following format:
+//
+//	keyword[ HH:MM[:SS]][(+|-)duration]
+//
+// where keyword is one of:
+//
+//   - "now": the current time
+//   - "today" or "startofday": the start of the current day
+//   - "tomorrow": the start of the next day
+//   - "yesterday": the start of the previous day
+//
+// The optional time of day (not allowed after "now") is added to the start of the day,
+// then the optional duration (parsed with [time.ParseDuration]) is added or subtracted.
+// For instance, "now-1h30m", "today 09:00" and "tomorrow 18:00+30m" are valid.
+//
+// It reports whether value starts with a keyword.
+func (a *applier) parseRelativeTime(value string) (time.Time, bool, error) {
+	keyword, rest := value, ""
+	if i := strings.IndexAny(value, " +-"); i >= 0 {
+		keyword, rest = value[:i], value[i:]
+	}
+
+	keyword = strings.ToLower(keyword)
+
+	var dayOffset int
+
+	switch keyword {
+	case "now":
+		if strings.HasPrefix(rest, " ") {
+			return time.Time{}, true, fmt.Errorf("%w: no time of day allowed after \"now\", got %q", ErrInvalidFormat, value)
+		}
+	case "today", "startofday":
+	case "tomorrow":
+		dayOffset = 1
+	case "yesterday":
+		dayOffset = -1
+	default:
+		return time.Time{}, false, nil
+	}
+
+	// The current time is only needed once value is known to be relative.
+	now := a.now()
+	year, month, day := now.Date()
+	day += dayOffset
+
+	result := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	if keyword == "now" {
+		result = now
+	}
+
+	if strings.HasPrefix(rest, " ") {
+		clock := rest[1:]
+		rest = ""
+
+		if i := strings.IndexAny(clock, "+-"); i >= 0 {
+			clock, rest = clock[:i], clock[i:]
+		}
+
+		timeOfDay, err := time.Parse(time.TimeOnly, clock)
+		if err != nil {
+			if timeOfDay, err = time.Parse("15:04", clock); err != nil {
+				return time.Time{}, true, fmt.Errorf("%w: invalid time of day %q", ErrInvalidFormat, clock)
+			}
+		}
+
+		result = time.Date(
+			year, month, day, timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), 0, now.Location(),
+		)
+	}
+
+	if rest != "" {
+		offset, err := time.ParseDuration(rest)
+		if err != nil {
+			return time.Time{}, true, err
+		}
+
+		result = result.Add(offset)
+	}
+
+	return result, true, nil
+}
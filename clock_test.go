@@ -0,0 +1,58 @@
+package defaults_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+func TestRelativeTimes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 15, 14, 30, 45, 0, time.UTC)
+	parser := defaults.NewParser(defaults.WithClock(func() time.Time { return now }))
+
+	var value struct {
+		Now       time.Time `default:"now"`
+		Past      time.Time `default:"now-1h30m"`
+		Today     time.Time `default:"today"`
+		Start     time.Time `default:"startofday"`
+		Opening   time.Time `default:"today 09:00"`
+		Tomorrow  time.Time `default:"tomorrow"`
+		Closing   time.Time `default:"tomorrow 18:00:30+30m"`
+		Yesterday time.Time `default:"yesterday-1h"`
+		Expiry    time.Time `default:"now+24h"`
+		Fixed     time.Time `default:"1982-04-12"`
+	}
+
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	for name, check := range map[string]struct{ got, expected time.Time }{
+		"Now":       {value.Now, now},
+		"Past":      {value.Past, time.Date(2024, 3, 15, 13, 0, 45, 0, time.UTC)},
+		"Today":     {value.Today, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		"Start":     {value.Start, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		"Opening":   {value.Opening, time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)},
+		"Tomorrow":  {value.Tomorrow, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		"Closing":   {value.Closing, time.Date(2024, 3, 16, 18, 30, 30, 0, time.UTC)},
+		"Yesterday": {value.Yesterday, time.Date(2024, 3, 13, 23, 0, 0, 0, time.UTC)},
+		"Expiry":    {value.Expiry, time.Date(2024, 3, 16, 14, 30, 45, 0, time.UTC)},
+		"Fixed":     {value.Fixed, time.Date(1982, 4, 12, 0, 0, 0, 0, time.UTC)},
+	} {
+		if !check.got.Equal(check.expected) {
+			t.Errorf("wrong value for %s: %s, expected %s", name, check.got, check.expected)
+		}
+	}
+
+	var invalid struct {
+		T time.Time `default:"today 25:00"`
+	}
+
+	if err := parser.Set(&invalid); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package defaults
+
+import (
+	"math/big"
+	"net/netip"
+	"reflect"
+	"time"
+)
+
+// immutableStructs are the struct types whose copies share no mutable state.
+//
+//nolint:gochecknoglobals // This is a lookup table.
+var immutableStructs = map[reflect.Type]bool{
+	reflect.TypeFor[time.Time]():      true,
+	reflect.TypeFor[netip.Addr]():     true,
+	reflect.TypeFor[netip.AddrPort](): true,
+	reflect.TypeFor[netip.Prefix]():   true,
+}
+
+// structCloners deep-copy the struct types whose copies would share mutable state.
+//
+//nolint:gochecknoglobals // This is a lookup table.
+var structCloners = map[reflect.Type]func(value reflect.Value) reflect.Value{
+	reflect.TypeFor[big.Int]():   cloneBig(func(dst, src *big.Int) { dst.Set(src) }),
+	reflect.TypeFor[big.Float](): cloneBig(func(dst, src *big.Float) { dst.Copy(src) }),
+	reflect.TypeFor[big.Rat]():   cloneBig(func(dst, src *big.Rat) { dst.Set(src) }),
+}
+
+// cloneBig returns a cloner for the math/big types, which must be copied with their own methods.
+func cloneBig[T any](set func(dst, src *T)) func(value reflect.Value) reflect.Value {
+	return func(value reflect.Value) reflect.Value {
+		src, _ := value.Interface().(T)
+		result := reflect.New(value.Type())
+
+		dst, _ := result.Interface().(*T)
+		set(dst, &src)
+
+		return result.Elem()
+	}
+}
+
+// isCloneable reports whether values of type typ may be deep-copied with cloneValue.
+func isCloneable(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String, reflect.Chan:
+		return true
+
+	case reflect.Array, reflect.Map, reflect.Pointer, reflect.Slice:
+		if visited[typ] {
+			return true
+		}
+
+		if visited == nil {
+			visited = map[reflect.Type]bool{}
+		}
+
+		visited[typ] = true
+
+		if typ.Kind() == reflect.Map && !isCloneable(typ.Key(), visited) {
+			return false
+		}
+
+		return isCloneable(typ.Elem(), visited)
+
+	case reflect.Struct:
+		_, ok := structCloners[typ]
+
+		return ok || immutableStructs[typ]
+
+	default:
+		return false
+	}
+}
+
+// cloneValue returns a deep copy of value, which must be cloneable or built by the parser, so that the copy shares no mutable state with value.
+// Channels are not copied: new channels are created with the same buffer size.
+func cloneValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Array:
+		if isScalar(value.Type().Elem()) {
+			return value
+		}
+
+		result := reflect.New(value.Type()).Elem()
+		for i := range value.Len() {
+			result.Index(i).Set(cloneValue(value.Index(i)))
+		}
+
+		return result
+
+	case reflect.Chan:
+		if value.IsNil() {
+			return value
+		}
+
+		return reflect.MakeChan(value.Type(), value.Cap())
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+
+		return result
+
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type().Elem())
+		result.Elem().Set(cloneValue(value.Elem()))
+
+		return result
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		if isScalar(value.Type().Elem()) {
+			reflect.Copy(result, value)
+
+			return result
+		}
+
+		for i := range value.Len() {
+			result.Index(i).Set(cloneValue(value.Index(i)))
+		}
+
+		return result
+
+	case reflect.Struct:
+		if cloner, ok := structCloners[value.Type()]; ok {
+			return cloner(value)
+		}
+
+		if immutableStructs[value.Type()] {
+			return value
+		}
+
+		// Other structs are only cloned when they have been built by the parser, with zero unexported fields.
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+
+		for i := range value.NumField() {
+			if value.Type().Field(i).IsExported() && !isScalar(value.Type().Field(i).Type) {
+				result.Field(i).Set(cloneValue(value.Field(i)))
+			}
+		}
+
+		return result
+
+	default:
+		return value
+	}
+}
+
+// isScalar reports whether values of type typ are copied by assignment.
+func isScalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Defaultsdoc renders the documentation of configuration structs from their tags,
+with [github.com/willoma/defaults.RenderDocs]: the path, type, default value
+and "doc" tag description of each field.
+
+Usage:
+
+	defaultsdoc -type T[,T...] [-pkg path] [-format markdown|text] [-output file]
+
+The types must be exported types of the package designated by its import path,
+by default the package in the current directory. Since the types are only known when compiled,
+defaultsdoc writes a temporary program importing the package in the current directory,
+which must be part of the module of the package, then runs it with "go run".
+
+It is meant to be run by go generate, to keep the documentation in sync with the tags:
+
+	//go:generate go run github.com/willoma/defaults/cmd/defaultsdoc -type Config -output CONFIG.md
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: defaultsdoc -type T[,T...] [-pkg path] [-format markdown|text] [-output file]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("defaultsdoc: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of type names; must be set")
+	pkg := flag.String("pkg", "", "import path of the package; default the package in the current directory")
+	format := flag.String("format", "markdown", "output format: markdown or text")
+	output := flag.String("output", "", "output file name; default standard output")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2) //nolint:mnd // This is the usual exit code for usage errors.
+	}
+
+	docs, err := run(*pkg, strings.Split(*typeNames, ","), *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(docs)
+	} else {
+		err = os.WriteFile(*output, docs, 0o644) //nolint:gosec // Documentation is world-readable.
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run renders the documentation of the named types of pkg, by running a temporary program.
+func run(pkg string, typeNames []string, format string) ([]byte, error) {
+	if pkg == "" {
+		list, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".").Output()
+		if err != nil {
+			return nil, fmt.Errorf("cannot find the package in the current directory: %w", err)
+		}
+
+		pkg = strings.TrimSpace(string(list))
+	}
+
+	src, err := program(pkg, typeNames, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// The program must be inside the module, to import the package with its dependencies.
+	dir, err := os.MkdirTemp(".", ".defaultsdoc-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0o600); err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, stderr.Bytes())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// program returns the source of the program rendering the documentation of the named types of pkg.
+func program(pkg string, typeNames []string, format string) ([]byte, error) {
+	var formatName, heading string
+
+	switch format {
+	case "markdown":
+		formatName, heading = "FormatMarkdown", "## %s\n\n"
+	case "text":
+		formatName, heading = "FormatText", "%s:\n\n"
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, `// Code generated by defaultsdoc; DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/willoma/defaults"
+	target %q
+)
+
+func main() {
+	docs := []struct {
+		name string
+		typ  reflect.Type
+	}{
+`, pkg)
+
+	for _, name := range typeNames {
+		fmt.Fprintf(&src, "\t\t{%q, reflect.TypeFor[target.%s]()},\n", name, name)
+	}
+
+	fmt.Fprintf(&src, `	}
+
+	for i, doc := range docs {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf(%q, doc.name)
+
+		if err := defaults.RenderDocs(os.Stdout, doc.typ, defaults.%s); err != nil {
+			fmt.Fprintf(os.Stderr, "%%s: %%s\n", doc.name, err)
+			os.Exit(1)
+		}
+	}
+}
+`, heading, formatName)
+
+	return src.Bytes(), nil
+}
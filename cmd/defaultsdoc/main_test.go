@@ -0,0 +1,53 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestProgram(t *testing.T) {
+	t.Parallel()
+
+	src, err := program("example.com/config", []string{"Config", "Server"}, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Errorf("invalid program: %s\n%s", err, src)
+	}
+
+	for _, expected := range []string{
+		`target "example.com/config"`,
+		`{"Config", reflect.TypeFor[target.Config]()},`,
+		`{"Server", reflect.TypeFor[target.Server]()},`,
+		"defaults.FormatText",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("missing %q in program:\n%s", expected, src)
+		}
+	}
+
+	if _, err := program("example.com/config", []string{"Config"}, "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("running the program needs to build it")
+	}
+
+	docs, err := run("github.com/willoma/defaults", []string{"FieldInfo"}, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"## FieldInfo\n", "| `Path` | `string` |  |  |\n"} {
+		if !strings.Contains(string(docs), expected) {
+			t.Errorf("missing %q in documentation:\n%s", expected, docs)
+		}
+	}
+}
@@ -0,0 +1,739 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
+	"math"
+	"net"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/willoma/defaults"
+	"github.com/willoma/defaults/internal/tags"
+)
+
+const (
+	defaultTagName = tags.Default
+	generatorName  = "defaultsgen"
+)
+
+var errUnsupported = errors.New("unsupported")
+
+// unsupportedTags are the tags whose behavior cannot be generated.
+//
+//nolint:gochecknoglobals // This is a lookup table.
+var unsupportedTags = []string{tags.Conditional, tags.JSON, tags.PointerMode, "required"}
+
+//nolint:gochecknoglobals // This is a lookup table.
+var basicTypes = map[string]reflect.Type{
+	"bool":       reflect.TypeFor[bool](),
+	"byte":       reflect.TypeFor[byte](),
+	"complex64":  reflect.TypeFor[complex64](),
+	"complex128": reflect.TypeFor[complex128](),
+	"float32":    reflect.TypeFor[float32](),
+	"float64":    reflect.TypeFor[float64](),
+	"int":        reflect.TypeFor[int](),
+	"int8":       reflect.TypeFor[int8](),
+	"int16":      reflect.TypeFor[int16](),
+	"int32":      reflect.TypeFor[int32](),
+	"int64":      reflect.TypeFor[int64](),
+	"rune":       reflect.TypeFor[rune](),
+	"string":     reflect.TypeFor[string](),
+	"uint":       reflect.TypeFor[uint](),
+	"uint8":      reflect.TypeFor[uint8](),
+	"uint16":     reflect.TypeFor[uint16](),
+	"uint32":     reflect.TypeFor[uint32](),
+	"uint64":     reflect.TypeFor[uint64](),
+}
+
+// knownType is a type of another package that has a specific parser.
+type knownType struct {
+	path string
+	expr string
+	typ  reflect.Type
+}
+
+//nolint:gochecknoglobals // This is a lookup table.
+var knownTypes = map[string]knownType{
+	"io/fs.FileMode":   {"io/fs", "fs.FileMode", reflect.TypeFor[fs.FileMode]()},
+	"net.HardwareAddr": {"net", "net.HardwareAddr", reflect.TypeFor[net.HardwareAddr]()},
+	"os.FileMode":      {"io/fs", "fs.FileMode", reflect.TypeFor[fs.FileMode]()},
+	"time.Duration":    {"time", "time.Duration", reflect.TypeFor[time.Duration]()},
+}
+
+// fieldType is the type of a field, as known by the generator.
+type fieldType struct {
+	// typ is the runtime equivalent of the type, used to parse the default values.
+	// It is nil for struct types of the package and the types containing them.
+	typ reflect.Type
+
+	// kind is the kind of the type, even if typ is nil.
+	kind reflect.Kind
+
+	// expr is the expression of the type in the generated file.
+	expr string
+
+	// converted is true when constants must be converted to the type.
+	converted bool
+
+	// local is the name of the struct type, when the type is a struct type of the package.
+	local string
+
+	key, elem *fieldType
+}
+
+// typeDecl is the declaration of a type of the package.
+type typeDecl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+}
+
+type generator struct {
+	fset    *token.FileSet
+	pkgName string
+	types   map[string]typeDecl
+
+	// methods holds the methods of the types of the package, except the generated ones.
+	methods map[string][]string
+
+	imports map[string]bool
+	queued  map[string]bool
+	queue   []string
+	errs    []error
+	body    bytes.Buffer
+}
+
+// generate returns the source of the methods of the named types of the package in dir.
+func generate(dir string, typeNames []string) ([]byte, error) {
+	gen, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range typeNames {
+		decl, ok := gen.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+
+		if _, isStruct := decl.spec.Type.(*ast.StructType); !isStruct {
+			return nil, fmt.Errorf("type %s is not a struct type", name)
+		}
+
+		gen.enqueue(name)
+	}
+
+	for len(gen.queue) > 0 {
+		name := gen.queue[0]
+		gen.queue = gen.queue[1:]
+		gen.generateType(name)
+	}
+
+	if len(gen.errs) > 0 {
+		return nil, errors.Join(gen.errs...)
+	}
+
+	return gen.source()
+}
+
+// load parses the package in dir.
+func load(dir string) (*generator, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := &generator{
+		fset:    token.NewFileSet(),
+		pkgName: pkg.Name,
+		types:   map[string]typeDecl{},
+		methods: map[string][]string{},
+		imports: map[string]bool{},
+		queued:  map[string]bool{},
+	}
+
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(gen.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if isGenerated(file) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			gen.addDecl(decl, file)
+		}
+	}
+
+	return gen, nil
+}
+
+// isGenerated reports whether file has been generated by this command.
+func isGenerated(file *ast.File) bool {
+	return ast.IsGenerated(file) && strings.Contains(file.Comments[0].Text(), generatorName)
+}
+
+func (g *generator) addDecl(decl ast.Decl, file *ast.File) {
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		if decl.Tok != token.TYPE {
+			return
+		}
+
+		for _, spec := range decl.Specs {
+			if spec, ok := spec.(*ast.TypeSpec); ok {
+				g.types[spec.Name.Name] = typeDecl{spec, file}
+			}
+		}
+
+	case *ast.FuncDecl:
+		if decl.Recv == nil || len(decl.Recv.List) == 0 {
+			return
+		}
+
+		recv := decl.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+
+		switch recvType := recv.(type) {
+		case *ast.Ident:
+			g.methods[recvType.Name] = append(g.methods[recvType.Name], decl.Name.Name)
+		case *ast.IndexExpr:
+			if ident, ok := recvType.X.(*ast.Ident); ok {
+				g.methods[ident.Name] = append(g.methods[ident.Name], decl.Name.Name)
+			}
+		}
+	}
+}
+
+func (g *generator) enqueue(name string) {
+	if !g.queued[name] {
+		g.queued[name] = true
+		g.queue = append(g.queue, name)
+	}
+}
+
+func (g *generator) errorf(pos token.Pos, format string, args ...any) {
+	g.errs = append(g.errs, fmt.Errorf("%s: %s", g.fset.Position(pos), fmt.Sprintf(format, args...)))
+}
+
+// statement is the code applying the default of a field, for SetDefaults and for CompleteDefaults.
+type statement struct {
+	set, complete string
+}
+
+func (g *generator) generateType(name string) {
+	decl := g.types[name]
+
+	if decl.spec.TypeParams != nil {
+		g.errorf(decl.spec.Pos(), "generic type %s is not supported", name)
+
+		return
+	}
+
+	for _, method := range []string{"SetDefaults", "CompleteDefaults", "AfterDefaults"} {
+		if slices.Contains(g.methods[name], method) {
+			g.errorf(decl.spec.Pos(), "type %s already has a %s method", name, method)
+		}
+	}
+
+	structType, _ := decl.spec.Type.(*ast.StructType)
+
+	var statements []statement
+
+	for _, field := range structType.Fields.List {
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+
+		if len(field.Names) == 0 {
+			names = append(names, tags.EmbeddedName(field.Type))
+		}
+
+		for _, fieldName := range names {
+			if !token.IsExported(fieldName) {
+				continue
+			}
+
+			if stmt, ok := g.generateField(decl.file, field, fieldName); ok {
+				statements = append(statements, stmt)
+			}
+		}
+	}
+
+	g.writeMethod(name, "SetDefaults", "sets the tagged defaults of t, overwriting existing values", statements,
+		func(stmt statement) string { return stmt.set })
+	g.writeMethod(name, "CompleteDefaults", "sets the tagged defaults of the zero fields of t", statements,
+		func(stmt statement) string { return stmt.complete })
+}
+
+func (g *generator) writeMethod(typeName, method, doc string, statements []statement, code func(statement) string) {
+	fmt.Fprintf(&g.body, "\n// %s %s.\nfunc (t *%s) %s() {\n", method, doc, typeName, method)
+
+	for _, stmt := range statements {
+		g.body.WriteString(code(stmt))
+		g.body.WriteByte('\n')
+	}
+
+	g.body.WriteString("}\n")
+}
+
+func (g *generator) generateField(file *ast.File, field *ast.Field, name string) (statement, bool) {
+	var tag reflect.StructTag
+
+	if field.Tag != nil {
+		rawTag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			g.errorf(field.Tag.Pos(), "field %s: invalid tag: %s", name, err)
+
+			return statement{}, false
+		}
+
+		tag = reflect.StructTag(rawTag)
+	}
+
+	for _, key := range tags.Keys(tag) {
+		if slices.Contains(unsupportedTags, key) || strings.HasPrefix(key, defaultTagName+".") {
+			g.errorf(field.Pos(), "field %s: tag %q is not supported by %s", name, key, generatorName)
+
+			return statement{}, false
+		}
+	}
+
+	value, hasDefault := tag.Lookup(defaultTagName)
+	if !hasDefault {
+		return g.generateNested(file, field, name)
+	}
+
+	typ, err := g.resolve(file, field.Type)
+	if err == nil && typ.local != "" {
+		err = fmt.Errorf("%w: struct literal", errUnsupported)
+	}
+
+	if err == nil {
+		var literal string
+		if literal, err = g.defaultLiteral(typ, value); err == nil {
+			return statement{
+				set:      fmt.Sprintf("t.%s = %s", name, literal),
+				complete: fmt.Sprintf("if %s {\nt.%s = %s\n}", zeroCondition("t."+name, typ), name, literal),
+			}, true
+		}
+	}
+
+	g.errorf(field.Pos(), "field %s: %s", name, err)
+
+	return statement{}, false
+}
+
+// generateNested returns the statements applying the defaults of a field without default value,
+// if it is a struct of the package or a collection of such structs.
+func (g *generator) generateNested(file *ast.File, field *ast.Field, name string) (statement, bool) {
+	typ, err := g.resolve(file, field.Type)
+	if err != nil {
+		// Untagged fields of unsupported types are left untouched.
+		return statement{}, false
+	}
+
+	call := func(format string) statement {
+		return statement{
+			set:      fmt.Sprintf(format, "SetDefaults"),
+			complete: fmt.Sprintf(format, "CompleteDefaults"),
+		}
+	}
+
+	switch {
+	case typ.local != "":
+		g.enqueue(typ.local)
+
+		return call("t." + name + ".%s()"), true
+
+	case typ.elem == nil || typ.kind == reflect.Pointer || typ.kind == reflect.Chan:
+		return statement{}, false
+
+	case typ.elem.local != "":
+		g.enqueue(typ.elem.local)
+
+		if typ.kind == reflect.Map {
+			return call("for key, value := range t." + name + " {\nvalue.%s()\nt." + name + "[key] = value\n}"), true
+		}
+
+		return call("for i := range t." + name + " {\nt." + name + "[i].%s()\n}"), true
+
+	case typ.elem.kind == reflect.Pointer && typ.elem.elem.local != "":
+		g.enqueue(typ.elem.elem.local)
+
+		return call("for _, value := range t." + name + " {\nif value != nil {\nvalue.%s()\n}\n}"), true
+
+	default:
+		return statement{}, false
+	}
+}
+
+// resolve returns the type designated by expr in file.
+func (g *generator) resolve(file *ast.File, expr ast.Expr) (*fieldType, error) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return g.resolve(file, expr.X)
+
+	case *ast.Ident:
+		if typ, ok := basicTypes[expr.Name]; ok {
+			return &fieldType{typ: typ, kind: typ.Kind(), expr: expr.Name}, nil
+		}
+
+		return g.resolveLocal(expr.Name)
+
+	case *ast.SelectorExpr:
+		return g.resolveSelector(file, expr)
+
+	case *ast.StarExpr:
+		elem, err := g.resolve(file, expr.X)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &fieldType{kind: reflect.Pointer, expr: "*" + elem.expr, elem: elem}
+		if elem.typ != nil {
+			result.typ = reflect.PointerTo(elem.typ)
+		}
+
+		return result, nil
+
+	case *ast.ArrayType:
+		return g.resolveArray(file, expr)
+
+	case *ast.MapType:
+		key, err := g.resolve(file, expr.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		elem, err := g.resolve(file, expr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &fieldType{kind: reflect.Map, expr: "map[" + key.expr + "]" + elem.expr, key: key, elem: elem}
+		if key.typ != nil && elem.typ != nil && key.typ.Comparable() {
+			result.typ = reflect.MapOf(key.typ, elem.typ)
+		}
+
+		return result, nil
+
+	case *ast.ChanType:
+		if expr.Dir != ast.SEND|ast.RECV {
+			return nil, fmt.Errorf("%w: unidirectional channel", errUnsupported)
+		}
+
+		elem, err := g.resolve(file, expr.Value)
+		if err != nil || elem.typ == nil {
+			return nil, fmt.Errorf("%w: channel element type", errUnsupported)
+		}
+
+		return &fieldType{
+			typ:  reflect.ChanOf(reflect.BothDir, elem.typ),
+			kind: reflect.Chan,
+			expr: "chan " + elem.expr,
+			elem: elem,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: type %T", errUnsupported, expr)
+	}
+}
+
+// resolveLocal returns the type of the package named name.
+// Struct types are only designated by their name, other types are resolved to their underlying type.
+func (g *generator) resolveLocal(name string) (*fieldType, error) {
+	decl, ok := g.types[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: type %s", errUnsupported, name)
+	}
+
+	if _, isStruct := decl.spec.Type.(*ast.StructType); isStruct {
+		return &fieldType{kind: reflect.Struct, expr: name, local: name}, nil
+	}
+
+	if decl.spec.TypeParams != nil || slices.Contains(g.methods[name], "UnmarshalText") {
+		return nil, fmt.Errorf("%w: type %s", errUnsupported, name)
+	}
+
+	underlying, err := g.resolve(decl.file, decl.spec.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if underlying.typ == nil {
+		return nil, fmt.Errorf("%w: type %s", errUnsupported, name)
+	}
+
+	if decl.spec.Assign.IsValid() {
+		// Aliases are the same type.
+		return underlying, nil
+	}
+
+	result := *underlying
+	result.expr = name
+	result.converted = true
+
+	// Specific parsers only apply to the exact type, derived types are parsed according to their kind.
+	for _, known := range knownTypes {
+		if result.typ == known.typ {
+			result.typ = kindType(result.typ)
+		}
+	}
+
+	return &result, nil
+}
+
+// kindType returns the unnamed type of the same kind as typ.
+func kindType(typ reflect.Type) reflect.Type {
+	switch typ.Kind() {
+	case reflect.Int64:
+		return reflect.TypeFor[int64]()
+	case reflect.Uint32:
+		return reflect.TypeFor[uint32]()
+	case reflect.Slice:
+		return reflect.SliceOf(typ.Elem())
+	default:
+		return typ
+	}
+}
+
+func (g *generator) resolveSelector(file *ast.File, expr *ast.SelectorExpr) (*fieldType, error) {
+	pkg, ok := expr.X.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("%w: type %T", errUnsupported, expr.X)
+	}
+
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := path[strings.LastIndexByte(path, '/')+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		if name != pkg.Name {
+			continue
+		}
+
+		known, ok := knownTypes[path+"."+expr.Sel.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: type %s.%s", errUnsupported, path, expr.Sel.Name)
+		}
+
+		g.imports[known.path] = true
+
+		return &fieldType{typ: known.typ, kind: known.typ.Kind(), expr: known.expr, converted: true}, nil
+	}
+
+	return nil, fmt.Errorf("%w: type %s.%s", errUnsupported, pkg.Name, expr.Sel.Name)
+}
+
+func (g *generator) resolveArray(file *ast.File, expr *ast.ArrayType) (*fieldType, error) {
+	elem, err := g.resolve(file, expr.Elt)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Len == nil {
+		result := &fieldType{kind: reflect.Slice, expr: "[]" + elem.expr, elem: elem}
+		if elem.typ != nil {
+			result.typ = reflect.SliceOf(elem.typ)
+		}
+
+		return result, nil
+	}
+
+	lit, ok := expr.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return nil, fmt.Errorf("%w: array length", errUnsupported)
+	}
+
+	length, err := strconv.ParseInt(lit.Value, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fieldType{kind: reflect.Array, expr: "[" + lit.Value + "]" + elem.expr, elem: elem}
+	if elem.typ != nil {
+		result.typ = reflect.ArrayOf(int(length), elem.typ)
+	}
+
+	return result, nil
+}
+
+// defaultLiteral parses value with the runtime parsers and returns it as a Go expression.
+func (g *generator) defaultLiteral(typ *fieldType, value string) (string, error) {
+	if typ.typ == nil {
+		return "", fmt.Errorf("%w: type %s", errUnsupported, typ.expr)
+	}
+
+	if _, _, ok := tags.Provider(value); ok {
+		return "", fmt.Errorf("%w: default value provider", errUnsupported)
+	}
+
+	if tags.IsDynamic(value) {
+		return "", fmt.Errorf("%w: reference in default value", errUnsupported)
+	}
+
+	// The tag is copied to a scratch struct, so that it is parsed exactly as it would be at runtime.
+	scratch := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "F",
+		Type: typ.typ,
+		Tag:  reflect.StructTag(defaultTagName + ":" + strconv.Quote(value)),
+	}}))
+
+	if err := defaults.Set(scratch.Interface()); err != nil {
+		return "", errors.New(strings.ReplaceAll(strings.TrimPrefix(err.Error(), "F: "), "\nF: ", "\n"))
+	}
+
+	return g.literal(scratch.Elem().Field(0), typ), nil
+}
+
+// literal returns value as a Go expression of type typ.
+func (g *generator) literal(value reflect.Value, typ *fieldType) string {
+	var lit string
+
+	switch value.Kind() {
+	case reflect.Bool:
+		lit = strconv.FormatBool(value.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit = strconv.FormatInt(value.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		lit = strconv.FormatUint(value.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		lit = g.floatLiteral(value.Float(), value.Type().Bits())
+
+	case reflect.Complex64, reflect.Complex128:
+		bits := value.Type().Bits() / 2 //nolint:mnd // Complex numbers are made of two floats.
+		lit = "complex(" + g.floatLiteral(real(value.Complex()), bits) + ", " +
+			g.floatLiteral(imag(value.Complex()), bits) + ")"
+
+	case reflect.String:
+		lit = strconv.Quote(value.String())
+
+	case reflect.Chan:
+		return fmt.Sprintf("make(%s, %d)", typ.expr, value.Cap())
+
+	case reflect.Pointer:
+		if value.IsNil() {
+			return "nil"
+		}
+
+		return fmt.Sprintf("func() %s {\nvar value %s = %s\n\nreturn &value\n}()",
+			typ.expr, typ.elem.expr, g.literal(value.Elem(), typ.elem))
+
+	case reflect.Array, reflect.Slice:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return "nil"
+		}
+
+		elems := make([]string, 0, value.Len())
+		for i := range value.Len() {
+			elems = append(elems, g.literal(value.Index(i), typ.elem))
+		}
+
+		return typ.expr + "{" + strings.Join(elems, ", ") + "}"
+
+	case reflect.Map:
+		if value.IsNil() {
+			return "nil"
+		}
+
+		entries := make([]string, 0, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			entries = append(entries, g.literal(iter.Key(), typ.key)+": "+g.literal(iter.Value(), typ.elem))
+		}
+
+		slices.Sort(entries)
+
+		return typ.expr + "{" + strings.Join(entries, ", ") + "}"
+	}
+
+	if typ.converted {
+		return typ.expr + "(" + lit + ")"
+	}
+
+	return lit
+}
+
+func (g *generator) floatLiteral(value float64, bits int) string {
+	switch {
+	case math.IsNaN(value):
+		g.imports["math"] = true
+
+		return "math.NaN()"
+	case math.IsInf(value, 1):
+		g.imports["math"] = true
+
+		return "math.Inf(1)"
+	case math.IsInf(value, -1):
+		g.imports["math"] = true
+
+		return "math.Inf(-1)"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, bits)
+	}
+}
+
+// zeroCondition returns the condition under which the value of expr, of type typ, is zero.
+func zeroCondition(expr string, typ *fieldType) string {
+	switch typ.kind {
+	case reflect.Bool:
+		return "!" + expr
+	case reflect.String:
+		return expr + ` == ""`
+	case reflect.Chan, reflect.Map, reflect.Pointer, reflect.Slice:
+		return expr + " == nil"
+	case reflect.Array:
+		return expr + " == " + typ.expr + "{}"
+	default:
+		return expr + " == 0"
+	}
+}
+
+// source returns the formatted generated file.
+func (g *generator) source() ([]byte, error) {
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, "// Code generated by %s; DO NOT EDIT.\n\npackage %s\n", generatorName, g.pkgName)
+
+	if len(g.imports) > 0 {
+		src.WriteString("\nimport (\n")
+
+		for _, path := range slices.Sorted(maps.Keys(g.imports)) {
+			fmt.Fprintf(&src, "\t%q\n", path)
+		}
+
+		src.WriteString(")\n")
+	}
+
+	src.Write(g.body.Bytes())
+
+	return format.Source(src.Bytes())
+}
@@ -0,0 +1,87 @@
+/*
+Defaultsgen generates reflection-free SetDefaults and CompleteDefaults methods
+for structs with "default" tags, so that [github.com/willoma/defaults.Set]
+and [github.com/willoma/defaults.Complete] do not parse the tags on every call.
+
+Usage:
+
+	defaultsgen -type T[,T...] [-output file] [directory]
+
+It is meant to be run by go generate, from the package declaring the types:
+
+	//go:generate go run github.com/willoma/defaults/cmd/defaultsgen -type Config
+
+The default values are parsed once, when generating the code, with the same parsers as at runtime,
+and written as typed constants. The output file is named after the first type by default
+(for instance "config_defaults.go").
+
+Nested struct types declared in the same package, and the elements of slices, arrays and maps
+of these types, have their own methods generated too. Pointers without a default are left untouched.
+Untagged fields of types declared in other packages are left untouched too,
+even if these types have tagged fields. Methods promoted from embedded fields cannot be told
+apart from generated ones, so the types embedding structs that have generated methods
+are still parsed with reflection, which uses the generated methods of the embedded structs.
+
+Only booleans, numbers, strings, [time.Duration], [io/fs.FileMode] and [net.HardwareAddr],
+types derived from them, and pointers, slices, arrays, maps and channels of them, may have defaults.
+Default values using providers or references, conditional, JSON and profile defaults,
+pointer modes, required fields and hooks are not supported: they are reported as errors,
+and these types must be handled by reflection.
+
+The generated methods are only called when the parser reads the "default" tag without profiles
+or pointer mode, and when no parser registered with RegisterParser or WithParser applies
+to the fields of the type (or of its nested structs): in these cases, the type is parsed
+with reflection, which uses the tags again.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: defaultsgen -type T[,T...] [-output file] [directory]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("defaultsgen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of type names; must be set")
+	output := flag.String("output", "", "output file name; default <directory>/<type>_defaults.go")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2) //nolint:mnd // This is the usual exit code for usage errors.
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+
+	src, err := generate(dir, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_defaults.go")
+	}
+
+	if err := os.WriteFile(name, src, 0o644); err != nil { //nolint:gosec // Go files are world-readable.
+		log.Fatal(err)
+	}
+}
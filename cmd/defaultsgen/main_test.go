@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validSource = `package config
+
+import (
+	"os"
+	"time"
+)
+
+type Level string
+
+type Backend struct {
+	Host string ` + "`default:\"localhost\"`" + `
+}
+
+type Config struct {
+	Name     string         ` + "`default:\"app\"`" + `
+	Timeout  time.Duration  ` + "`default:\"1m30s\"`" + `
+	Mode     os.FileMode    ` + "`default:\"644\"`" + `
+	Level    Level          ` + "`default:\"info\"`" + `
+	Limits   map[string]int ` + "`default:\"b:2,a:1\"`" + `
+	Retries  *int           ` + "`default:\"3\"`" + `
+	Backend  Backend
+	Backends []Backend
+	Optional *Backend
+	Callback func()
+	internal string ` + "`default:\"ignored\"`" + `
+}
+`
+
+const invalidSource = `package config
+
+type Config struct {
+	Count    int    ` + "`default:\"abc\"`" + `
+	Host     string ` + "`default:\"@hostname\"`" + `
+	URL      string ` + "`default:\"http://${Host}\"`" + `
+	Port     int    ` + "`default:\"80\" default.prod:\"443\"`" + `
+	Callback func() ` + "`default:\"x\"`" + `
+}
+`
+
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	dir := writePackage(t, validSource)
+
+	generated, err := generate(dir, []string{"Config"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLines := []string{
+		"func (t *Config) SetDefaults() {",
+		"func (t *Config) CompleteDefaults() {",
+		"func (t *Backend) SetDefaults() {",
+		"t.Timeout = time.Duration(90000000000)",
+		"t.Mode = fs.FileMode(420)",
+		`t.Level = Level("info")`,
+		`t.Limits = map[string]int{"a": 1, "b": 2}`,
+		`if t.Name == "" {`,
+		"t.Backend.CompleteDefaults()",
+		"t.Backends[i].SetDefaults()",
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(string(generated), line) {
+			t.Errorf("missing %q in generated code:\n%s", line, generated)
+		}
+	}
+
+	for _, unexpected := range []string{"internal", "Optional", "Callback"} {
+		if strings.Contains(string(generated), unexpected) {
+			t.Errorf("unexpected %q in generated code:\n%s", unexpected, generated)
+		}
+	}
+
+	// The generated code must compile with the original code.
+	fset := token.NewFileSet()
+
+	var files []*ast.File
+
+	for name, src := range map[string]string{"config.go": validSource, "config_defaults.go": string(generated)} {
+		file, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("config", fset, files, nil); err != nil {
+		t.Errorf("generated code does not compile: %s", err)
+	}
+
+	// Generated files are ignored when generating again.
+	if err := os.WriteFile(filepath.Join(dir, "config_defaults.go"), generated, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := generate(dir, []string{"Config"}); err != nil {
+		t.Errorf("unexpected error when generating again: %s", err)
+	}
+}
+
+func TestGenerateUpToDate(t *testing.T) {
+	t.Parallel()
+
+	// The generated methods tested by the defaults package must match the current output.
+	dir := filepath.Join("..", "..", "internal", "testdata", "generated")
+
+	generated, err := generate(dir, []string{"Config"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile(filepath.Join(dir, "config_defaults.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(generated, committed) {
+		t.Errorf("%s is out of date, run go generate:\n%s", filepath.Join(dir, "config_defaults.go"), generated)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := generate(writePackage(t, invalidSource), []string{"Config"})
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+
+	expected := []string{
+		"field Count: strconv.Atoi",
+		"field Host: unsupported: default value provider",
+		"field URL: unsupported: reference in default value",
+		`field Port: tag "default.prod" is not supported`,
+		"field Callback: unsupported",
+	}
+
+	for _, message := range expected {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("missing %q in %s", message, err)
+		}
+	}
+}
@@ -7,12 +7,12 @@ import (
 	"strings"
 )
 
-func parseArray(target reflect.Value, value string, hasDefault bool) (reflect.Value, []error) {
+func (a *applier) parseArray(target reflect.Value, value string, hasDefault bool) (reflect.Value, []error) {
 	if !hasDefault {
 		return reflect.Value{}, nil
 	}
 
-	defaults := asList(value)
+	defaults := a.splitList(value, target.Type().Elem())
 
 	if tgtLen, defLen := target.Len(), len(defaults); defLen != tgtLen {
 		return reflect.Value{}, []error{fmt.Errorf("%w: expected %d values, got %d", ErrInvalidFormat, tgtLen, defLen)}
@@ -20,30 +20,30 @@ func parseArray(target reflect.Value, value string, hasDefault bool) (reflect.Va
 
 	arrayType := target.Type()
 	result := reflect.New(arrayType).Elem()
-	errs := parseList(arrayType.Elem(), result, defaults)
+	errs := a.parseList(arrayType.Elem(), result, defaults)
 
 	return result, errs
 }
 
-func parseSlice(target reflect.Value, value string, hasDefault bool) (reflect.Value, []error) {
+func (a *applier) parseSlice(target reflect.Value, value string, hasDefault bool) (reflect.Value, []error) {
 	if !hasDefault {
 		return reflect.Value{}, nil
 	}
 
-	defaults := asList(value)
+	defaults := a.splitList(value, target.Type().Elem())
 	result := reflect.MakeSlice(target.Type(), len(defaults), len(defaults))
-	errs := parseList(target.Type().Elem(), result, defaults)
+	errs := a.parseList(target.Type().Elem(), result, defaults)
 
 	return result, errs
 }
 
-func parseList(itemsType reflect.Type, result reflect.Value, defaults []string) []error {
+func (a *applier) parseList(itemsType reflect.Type, result reflect.Value, defaults []string) []error {
 	var errs []error
 
 	zero := reflect.Zero(itemsType)
 
 	for i, def := range defaults {
-		value, err := parse(zero, def, true, true)
+		value, err := a.parseLiteralItem(zero, def)
 		if len(err) > 0 {
 			errs = append(errs, addErrorsPrefixes(strconv.Itoa(i), err)...)
 
@@ -56,23 +56,48 @@ func parseList(itemsType reflect.Type, result reflect.Value, defaults []string)
 	return errs
 }
 
-func parseMap(target reflect.Value, defaultValue string, hasDefaults bool) (reflect.Value, []error) {
+func (a *applier) parseMap(target reflect.Value, defaultValue string, hasDefaults bool) (reflect.Value, []error) {
 	if !hasDefaults {
 		return reflect.Value{}, nil
 	}
 
 	var errs []error
 
-	defaults := asList(defaultValue)
 	targetType := target.Type()
-	result := reflect.MakeMapWithSize(targetType, len(defaults))
 	keyType := targetType.Key()
 	valueType := targetType.Elem()
 	keyZero := reflect.Zero(keyType)
 	valueZero := reflect.Zero(valueType)
+	nested := a.isComposite(keyType) || a.isComposite(valueType)
+
+	if defaultValue == "{}" {
+		return reflect.MakeMap(targetType), nil
+	}
+
+	// The braces may only be part of the first key and of the last value if both accept them.
+	if !a.hasDelimiters(keyType) || !a.hasDelimiters(valueType) {
+		var enclosed bool
+		if defaultValue, enclosed = trimDelimiters(defaultValue, '{', '}'); enclosed && defaultValue == "" {
+			return reflect.MakeMap(targetType), nil
+		}
+	}
+
+	var defaults []string
+	if nested {
+		defaults = splitTopLevel(defaultValue, ',', -1)
+	} else {
+		defaults = asList(defaultValue)
+	}
+
+	result := reflect.MakeMapWithSize(targetType, len(defaults))
 
 	for i, def := range defaults {
-		keyValue := strings.SplitN(def, ":", 2)
+		var keyValue []string
+		if nested {
+			keyValue = splitTopLevel(def, ':', 2)
+		} else {
+			keyValue = strings.SplitN(def, ":", 2)
+		}
 
 		if len(keyValue) != 2 {
 			errs = append(
@@ -83,16 +108,21 @@ func parseMap(target reflect.Value, defaultValue string, hasDefaults bool) (refl
 			continue
 		}
 
+		if nested {
+			keyValue[0] = a.literalItem(keyType, keyValue[0])
+			keyValue[1] = a.literalItem(valueType, keyValue[1])
+		}
+
 		var fail bool
 
-		key, err := parse(keyZero, keyValue[0], true, true)
+		key, err := a.parseLiteralItem(keyZero, keyValue[0])
 		if len(err) > 0 {
 			errs = append(errs, addErrorsPrefixes(strconv.Itoa(i), err)...)
 
 			fail = true
 		}
 
-		value, err := parse(valueZero, keyValue[1], true, true)
+		value, err := a.parseLiteralItem(valueZero, keyValue[1])
 		if len(err) > 0 {
 			errs = append(errs, addErrorsPrefixes(strconv.Itoa(i), err)...)
 
@@ -109,6 +139,103 @@ func parseMap(target reflect.Value, defaultValue string, hasDefaults bool) (refl
 	return result, errs
 }
 
+// parseElements applies the defaults of the element type to each existing element
+// of target, if it is a slice, an array or a map, or the defaults of its fields if it is a struct.
+// Map values are not addressable: they are copied, modified, then stored back in the map,
+// unless validating or checking.
+func (a *applier) parseElements(target reflect.Value, overwrite bool) []error {
+	switch target.Kind() {
+	case reflect.Array, reflect.Slice:
+		if !a.hasElementDefaults(target.Type().Elem(), nil) {
+			return nil
+		}
+
+		var errs []error
+
+		for i := range target.Len() {
+			if err := a.parseElement(target.Index(i), overwrite); len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(strconv.Itoa(i), err)...)
+			}
+		}
+
+		return errs
+
+	case reflect.Struct:
+		if a.hasParser(target.Type()) {
+			return nil
+		}
+
+		_, errs := a.parseStruct(target, overwrite)
+
+		return errs
+
+	case reflect.Map:
+		if !a.hasElementDefaults(target.Type().Elem(), nil) {
+			return nil
+		}
+
+		var errs []error
+
+		iter := target.MapRange()
+		for iter.Next() {
+			value := reflect.New(target.Type().Elem()).Elem()
+			value.Set(iter.Value())
+
+			if err := a.parseElement(value, overwrite); len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(fmt.Sprint(iter.Key().Interface()), err)...)
+			}
+
+			// Validating and checking only read the values, and must not write to the caller's maps.
+			if !a.validating && !a.checking {
+				target.SetMapIndex(iter.Key(), value)
+			}
+		}
+
+		return errs
+
+	default:
+		return nil
+	}
+}
+
+// parseElement applies the defaults of its type to an existing collection element.
+func (a *applier) parseElement(target reflect.Value, overwrite bool) []error {
+	if target.Kind() != reflect.Pointer {
+		return a.parseElements(target, overwrite)
+	}
+
+	if target.IsNil() {
+		return nil
+	}
+
+	return a.parseElement(target.Elem(), overwrite)
+}
+
+// hasElementDefaults reports whether collection elements of type typ may receive defaults,
+// that is if they are, point to or contain structs.
+func (a *applier) hasElementDefaults(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Struct:
+		return !a.hasParser(typ)
+
+	case reflect.Array, reflect.Map, reflect.Pointer, reflect.Slice:
+		if visited[typ] {
+			return false
+		}
+
+		if visited == nil {
+			visited = map[reflect.Type]bool{}
+		}
+
+		visited[typ] = true
+
+		return a.hasElementDefaults(typ.Elem(), visited)
+
+	default:
+		return false
+	}
+}
+
 // asList converts a comma-separated string to a list. A comma may be escaped with a backslash.
 func asList(src string) []string {
 	var result []string
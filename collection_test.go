@@ -0,0 +1,85 @@
+package defaults_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type backend struct {
+	Host   string `default:"localhost"`
+	Port   int    `default:"80"`
+	Weight int    `default:"1"`
+}
+
+func TestCollectionElements(t *testing.T) {
+	t.Parallel()
+
+	value := struct {
+		Servers  []backend
+		Pinned   [2]backend
+		Backends map[string]*backend
+		Zones    map[string]backend
+		Nested   [][]backend
+	}{
+		Servers:  []backend{{Host: "one"}, {Port: 8080}},
+		Pinned:   [2]backend{{Weight: 5}},
+		Backends: map[string]*backend{"api": {Host: "api"}, "none": nil},
+		Zones:    map[string]backend{"eu": {Port: 443}},
+		Nested:   [][]backend{{{Host: "deep"}}},
+	}
+
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Servers[0] != (backend{"one", 80, 1}) || value.Servers[1] != (backend{"localhost", 8080, 1}) {
+		t.Errorf("wrong value for Servers: %+v", value.Servers)
+	}
+
+	if value.Pinned != [2]backend{{"localhost", 80, 5}, {"localhost", 80, 1}} {
+		t.Errorf("wrong value for Pinned: %+v", value.Pinned)
+	}
+
+	if *value.Backends["api"] != (backend{"api", 80, 1}) || value.Backends["none"] != nil {
+		t.Errorf("wrong value for Backends: %+v", value.Backends)
+	}
+
+	if value.Zones["eu"] != (backend{"localhost", 443, 1}) {
+		t.Errorf("wrong value for Zones: %+v", value.Zones)
+	}
+
+	if value.Nested[0][0] != (backend{"deep", 80, 1}) {
+		t.Errorf("wrong value for Nested: %+v", value.Nested)
+	}
+}
+
+func TestCollectionEmpty(t *testing.T) {
+	t.Parallel()
+
+	// "[]" and "{}" are empty, even when brackets and braces may be part of the values.
+	var value struct {
+		Strings  []string          `default:"[]"`
+		Ints     []int             `default:"[]"`
+		Labels   map[string]string `default:"{}"`
+		Limits   map[string]int    `default:"{}"`
+		Brackets []string          `default:"\\[]"`
+	}
+
+	if err := defaults.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Strings == nil || len(value.Strings) != 0 || value.Ints == nil || len(value.Ints) != 0 {
+		t.Errorf("wrong values for Strings and Ints: %q, %v", value.Strings, value.Ints)
+	}
+
+	if value.Labels == nil || len(value.Labels) != 0 || value.Limits == nil || len(value.Limits) != 0 {
+		t.Errorf("wrong values for Labels and Limits: %q, %v", value.Labels, value.Limits)
+	}
+
+	if !slices.Equal(value.Brackets, []string{"[]"}) {
+		t.Errorf("wrong value for Brackets: %q", value.Brackets)
+	}
+}
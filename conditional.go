@@ -0,0 +1,128 @@
+package defaults
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+const conditionalTagName = tags.Conditional
+
+// condition is a conditional default rule, like "TLS=true:443" or "Driver!=sqlite:localhost".
+type condition struct {
+	path   string
+	value  string
+	negate bool
+	result string
+}
+
+// parseConditions parses the rules of a "default_if" tag, separated by semicolons.
+// Semicolons and colons in conditions may be escaped with a backslash.
+func parseConditions(tag string) ([]condition, error) {
+	var conditions []condition
+
+	for _, rule := range splitTopLevel(tag, ';', -1) {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		test, result, found := cutUnescaped(rule, ':')
+		if !found {
+			return nil, fmt.Errorf("%w: expected \"<field>=<value>:<default>\", got %q", ErrInvalidFormat, rule)
+		}
+
+		var cond condition
+
+		path, value, found := strings.Cut(test, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: expected \"<field>=<value>\" condition, got %q", ErrInvalidFormat, test)
+		}
+
+		if strings.HasSuffix(path, "!") {
+			path = path[:len(path)-1]
+			cond.negate = true
+		}
+
+		cond.path = strings.TrimSpace(path)
+		cond.value = unescape(value)
+		cond.result = strings.ReplaceAll(result, `\;`, ";")
+
+		conditions = append(conditions, cond)
+	}
+
+	return conditions, nil
+}
+
+// cutUnescaped slices src around the first occurrence of sep that is not escaped with a backslash.
+func cutUnescaped(src string, sep rune) (before, after string, found bool) {
+	var escaped bool
+
+	for i, char := range src {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == sep:
+			return src[:i], src[i+1:], true
+		}
+	}
+
+	return src, "", false
+}
+
+// conditionalDefault returns the default value of the first rule of the "default_if" tag
+// whose condition matches, and reports whether a rule matched.
+func (a *applier) conditionalDefault(typeField reflect.StructField) (string, bool, error) {
+	tag, ok := typeField.Tag.Lookup(conditionalTagName)
+	if !ok {
+		return "", false, nil
+	}
+
+	conditions, err := parseConditions(tag)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, cond := range conditions {
+		matches, err := a.matches(cond)
+		if err != nil {
+			return "", false, err
+		}
+
+		if matches {
+			return cond.result, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// matches reports whether the condition matches the current value of the referenced field.
+// The expected value is parsed as a value of the field type, then both values are compared
+// in their formatted form, so that for instance "t" and "true" are equivalent for booleans.
+func (a *applier) matches(cond condition) (bool, error) {
+	current, found, err := a.lookupField(cond.path)
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, fmt.Errorf("%w: %q", ErrUnknownReference, cond.path)
+	}
+
+	expected := cond.value
+
+	if current.IsValid() {
+		parsed, errs := a.parse(reflect.New(current.Type()).Elem(), cond.value, true, true)
+		if len(errs) > 0 {
+			return false, fmt.Errorf("condition on %s: %w", cond.path, errs[0])
+		}
+
+		expected = formatValue(parsed)
+	}
+
+	return (formatValue(current) == expected) != cond.negate, nil
+}
@@ -0,0 +1,62 @@
+package defaults_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type conditionalConfig struct {
+	TLSPort int    `default:"80"  default_if:"TLS=true:443"`
+	DSN     string `default_if:"Driver=postgres:postgres://localhost/app;Driver=sqlite:file:app.db"`
+	Driver  string `default:"postgres"`
+	TLS     bool
+	Mode    string `default:"plain" default_if:"Driver!=sqlite:pooled\\;shared"`
+}
+
+func TestConditionalDefaults(t *testing.T) {
+	t.Parallel()
+
+	value := conditionalConfig{TLS: true}
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	expected := conditionalConfig{443, "postgres://localhost/app", "postgres", true, "pooled;shared"}
+	if value != expected {
+		t.Errorf("wrong value: %+v, expected %+v", value, expected)
+	}
+
+	value = conditionalConfig{Driver: "sqlite"}
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	expected = conditionalConfig{80, "file:app.db", "sqlite", false, "plain"}
+	if value != expected {
+		t.Errorf("wrong value: %+v, expected %+v", value, expected)
+	}
+
+	var unknown struct {
+		Port int `default_if:"Missing=true:443"`
+	}
+
+	err := defaults.Set(&unknown)
+	if !errors.Is(err, defaults.ErrUnknownReference) {
+		t.Fatalf("expected ErrUnknownReference, got %v", err)
+	}
+
+	if err.Error() != `Port: unknown field reference: "Missing"` {
+		t.Errorf("wrong error message: %q", err.Error())
+	}
+
+	var invalid struct {
+		Port int `default_if:"TLS:443"`
+		TLS  bool
+	}
+
+	if err := defaults.Set(&invalid); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
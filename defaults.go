@@ -48,7 +48,85 @@ The following types have specific parsers:
     [time.DateTime] ("YYYY-MM-DD HH:MM:SS"),
     [time.DateOnly] ("YYYY-MM-DD"),
     [time.TimeOnly] ("HH:MM:SS"),
-    or "HH:MM"
+    or "HH:MM",
+    or relatively to the current time with the "now", "today" (or "startofday"),
+    "tomorrow" and "yesterday" keywords, optionally followed by a time of day
+    and an offset (for instance "now-1h30m" or "tomorrow 09:00+30m");
+    the current time may be injected with [WithClock]
+
+Default values may be computed at runtime by providers, with the "@name" syntax,
+for instance `default:"@hostname"` or `default:"@randomhex:32"`.
+See [RegisterProvider] for the list of built-in providers.
+
+Default values may reference other fields with the "${FieldPath}" syntax,
+for instance `default:"http://${Host}:${Port}"`. Paths are dot-separated field names,
+resolved in the struct of the field, then in its ancestors.
+Fields are parsed after the fields they reference, whatever their declaration order,
+so that references see defaulted (or user-set) values.
+References to unknown fields and cycles are reported as errors.
+
+Default values may also reference environment variables, with a shell-like syntax:
+"$NAME" or "${NAME}" expand to the value of the NAME environment variable,
+and "${NAME:-fallback}" expands to "fallback" when NAME is unset or empty.
+Braced references designate a field when their path matches one,
+and an environment variable otherwise, but only if their name is written like an environment variable,
+with upper-case letters, digits and underscores: "${Hots}" is reported as an unknown reference
+rather than read from the environment. "$NAME" is kept as written when NAME is unset,
+while "${NAME}" without fallback is reported as an unknown reference. "$$" expands to a literal "$".
+The environment lookup may be replaced with [WithLookupEnv].
+
+Fields may have per-profile defaults, in tags named after the default tag and the profile,
+for instance `default:"4" default.prod:"100"`. The active profiles are selected with [WithProfiles],
+either when creating a [Parser] or when calling [Set] or [Complete].
+The first active profile having a tag wins, and fields without profile tags use their base tag.
+
+Defaults may depend on the values of other fields, with rules in the "default_if" tag,
+in the "<field>=<value>:<default>" format (or "<field>!=<value>:<default>"),
+separated by semicolons, for instance `default:"80" default_if:"TLS=true:443"`.
+The field paths are resolved like references, and fields are parsed after the fields their rules refer to.
+The first matching rule provides the default value, and the base tag is used if no rule matches.
+
+Pointers to structs that have no default value are left untouched,
+unless configured otherwise with [WithPointerMode] or with the "default_ptr" tag
+(for instance `default_ptr:"allocate"`), see [PointerMode].
+
+Fields that have no default and must be provided may be marked with the "required" tag
+(`required:""` or `required:"true"`): [Set] and [Complete] return an error wrapping [ErrRequired]
+for each of these fields that is still zero once defaults are applied.
+
+Once defaults are applied, values may be checked against validation tags
+(min, max, len, oneof, pattern and nonzero) with [Validate] or [CompleteAndValidate].
+
+Structs may compute defaults in code by implementing [Defaulter] or [FallibleDefaulter],
+whose SetDefaults method is called before the tagged defaults are applied,
+and [AfterDefaulter], whose AfterDefaults method is called after the tagged defaults
+(including those of nested structs) are applied.
+Errors returned by these methods are reported with the path of the struct.
+
+Types with many short-lived values may avoid reflection altogether,
+by generating SetDefaults and CompleteDefaults methods with the defaultsgen command
+(see [GeneratedDefaulter]), which [Set] and [Complete] call automatically.
+
+The defaults of a type may be listed with [Describe], for instance to print them at startup
+or to render help, with a description read from the "doc" tag.
+[RenderDocs] renders them as a Markdown table or as plain text,
+and the defaultsdoc command renders them from go generate.
+[JSONSchema] generates a JSON Schema of a type for configuration editors,
+with the default values and the validation tags.
+
+Each struct type is compiled once, on first use: the default values that do not depend
+on the context are parsed in advance, then deep-copied into each target.
+A type may also be compiled beforehand with [Compile], to report invalid defaults at startup,
+and objects recycled with a [sync.Pool] may be reset from a defaulted prototype with [Reset].
+
+Parsers for other types may be registered with [RegisterParser].
+Registered parsers take precedence over all the parsers listed above.
+
+The package-level functions use a default configuration.
+Libraries that need their own parsers or options should create a scoped [Parser]
+with [NewParser], so that they do not interfere with other users of this package.
+For instance, a Parser may read defaults from other struct tags than "default"
+(see [WithTagNames]), which avoids collisions with libraries that also use this tag.
 
 Slices and arrays of all these types are supported,
 with defaults values separated by commas
@@ -60,6 +138,34 @@ with defaults values separated by commas,
 each value being a key-value pair separated by a colon
 (for instance "one:1,two:2,three:3" for {"one": 1, "two": 2, "three": 3}).
 
+Slices, arrays, maps and structs may be nested, with a bracketed literal grammar:
+lists are enclosed in brackets, maps and structs are enclosed in braces,
+struct fields are given as "Field:value" pairs and unlisted fields receive their own defaults.
+For instance:
+
+  - "[[a,b],[c]]" for [][]string{{"a", "b"}, {"c"}}
+  - "{web:[80,443],ssh:[22]}" for map[string][]int{"web": {80, 443}, "ssh": {22}}
+  - "[{X:1,Y:2},{X:3}]" for a slice of structs
+  - "[]" and "{}" for empty slices and maps, whatever the type of their elements
+
+The outer brackets or braces are optional, which keeps the flat syntax valid.
+For compatibility with the flat syntax, they are part of the values when the items of a list,
+or both the keys and the values of a map, are strings or types with their own parser:
+`default:"[debug]"` sets a []string to {"[debug]"}, while `default:"[]"` sets it to an empty slice,
+and `default:"\\[]"` to {"[]"}. Nested lists and maps are always delimited.
+Brackets, braces, commas and colons may be escaped with a backslash.
+
+Complex defaults may also be written in JSON, in the "default_json" tag,
+which is used when the field has no "default" tag. The JSON document is converted
+to the field type with the parsers listed above, so that, for instance,
+`default_json:"{\"timeout\":\"5s\"}"` sets a [time.Duration] field named Timeout to 5 seconds.
+Object keys match struct fields by their "json" tag or by their name,
+and fields missing from objects receive their own defaults.
+
+When slices, arrays and maps are not replaced by their default value,
+the defaults of their elements type are applied to their existing elements,
+which is useful to complete structs decoded from configuration files.
+
 Defaults are unsupported for the following types:
 
   - uintptrs
@@ -87,29 +193,66 @@ Example:
 */
 package defaults
 
-import (
-	"errors"
-	"reflect"
-)
+import "reflect"
 
 // Set unmarshals the tagged defaults and applies them, overwriting existing values.
-func Set(target any) error { return apply(target, true) }
+// The options, if any, apply to this call only.
+func Set(target any, opts ...Option) error { return defaultParser.Set(target, opts...) }
 
 // Complete unmarshals the tagged defaults and applies them to unset values, leaving non-zero values untouched.
-func Complete(target any) error { return apply(target, false) }
+// The options, if any, apply to this call only.
+func Complete(target any, opts ...Option) error { return defaultParser.Complete(target, opts...) }
 
-func apply(target any, overwrite bool) error {
-	val := reflect.ValueOf(target)
-	if val.Kind() != reflect.Pointer {
-		return ErrMustBePointerToAStruct
-	}
+// Validate checks the values of target, which must be a pointer to a struct,
+// against their validation tags, without applying any default.
+// Nested structs, pointers to structs and collections of structs are validated too.
+//
+// The following validation tags are supported:
+//
+//   - min and max: bounds of numbers (parsed like default values, so durations may be used),
+//     or of the length of strings, slices, arrays, maps and channels
+//   - len: exact length of strings, slices, arrays, maps and channels
+//   - oneof: comma-separated list of allowed values
+//   - pattern: regular expression the formatted value must match
+//   - nonzero: the value must not be the zero value (`nonzero:""` or `nonzero:"true"`)
+//
+// Fields with the "required" tag are checked too, and are reported with [ErrRequired] when zero.
+//
+// Pointers are validated through the value they point to, and nil pointers are only checked by nonzero.
+// All errors are returned, joined, prefixed with the path of the field, and wrap [ErrInvalidValue].
+func Validate(target any) error { return defaultParser.Validate(target) }
 
-	elem := val.Elem()
-	if elem.Kind() != reflect.Struct {
-		return ErrMustBePointerToAStruct
+// CompleteAndValidate applies the defaults to unset values like [Complete],
+// then checks the values against their validation tags like [Validate].
+// The options, if any, apply to this call only.
+func CompleteAndValidate(target any, opts ...Option) error {
+	return defaultParser.CompleteAndValidate(target, opts...)
+}
+
+// New returns a new value of type T with the tagged defaults applied.
+//
+// T must be a struct type or a pointer to a struct type. In the latter case,
+// the pointed struct is allocated. The options, if any, apply to this call only.
+func New[T any](opts ...Option) (T, error) {
+	var result T
+
+	if typ := reflect.TypeFor[T](); typ.Kind() == reflect.Pointer {
+		ptr := reflect.New(typ.Elem())
+		result, _ = ptr.Interface().(T)
+
+		return result, Set(result, opts...)
 	}
 
-	_, errs := parseStruct(elem, overwrite)
+	return result, Set(&result, opts...)
+}
+
+// MustNew is like [New] but panics if the defaults cannot be applied.
+// It simplifies the initialization of package-level variables and test fixtures.
+func MustNew[T any](opts ...Option) T {
+	result, err := New[T](opts...)
+	if err != nil {
+		panic(err)
+	}
 
-	return errors.Join(errs...)
+	return result
 }
@@ -1,6 +1,7 @@
 package defaults_test
 
 import (
+	"errors"
 	"io/fs"
 	"maps"
 	"math/big"
@@ -185,3 +186,34 @@ func TestApply(t *testing.T) {
 		t.Errorf("wrong value for Z: %t", value.Z)
 	}
 }
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Name string        `default:"Willow"`
+		TTL  time.Duration `default:"5m"`
+	}
+
+	value, err := defaults.New[config]()
+	if err != nil {
+		t.Fatalf("failed to create value: %s", err)
+	}
+
+	if value.Name != "Willow" || value.TTL != 5*time.Minute {
+		t.Errorf("wrong value: %+v", value)
+	}
+
+	ptr := defaults.MustNew[*config]()
+	if ptr == nil {
+		t.Fatal("pointer is nil")
+	}
+
+	if ptr.Name != "Willow" || ptr.TTL != 5*time.Minute {
+		t.Errorf("wrong pointed value: %+v", *ptr)
+	}
+
+	if _, err := defaults.New[int](); !errors.Is(err, defaults.ErrMustBePointerToAStruct) {
+		t.Errorf("expected ErrMustBePointerToAStruct, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package defaults
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+const docTagName = "doc"
+
+// FieldInfo describes a field and its default value.
+type FieldInfo struct {
+	// Path is the dot-separated path of the field, as used in the errors returned by [CheckType].
+	// Since it describes a type rather than a value, "*" stands for the elements of slices, arrays and maps:
+	// the errors returned when applying defaults or validating use their actual index or key instead,
+	// so that "Servers.*.Port" describes the fields reported as "Servers.0.Port", "Servers.1.Port" and so on.
+	Path string
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Tag is the whole struct tag of the field.
+	Tag reflect.StructTag
+
+	// Default is the raw default value, from the default tag (or the tag of an active profile),
+	// or from the "default_json" tag.
+	Default string
+
+	// HasDefault reports whether the field has a default value.
+	HasDefault bool
+
+	// Value is the parsed default value, or nil if the field has no default value,
+	// if it is dynamic, or if it is invalid.
+	Value any
+
+	// Dynamic reports whether the default value is only known when defaults are applied,
+	// because it depends on conditions, providers, references, environment variables or the current time.
+	Dynamic bool
+
+	// Doc is the description of the field, from its "doc" tag.
+	Doc string
+
+	// Err is the error returned when parsing the default value, if any.
+	Err error
+}
+
+// Describe returns the description of every exported field of T, which must be a struct type
+// or a pointer to a struct type, including the fields of nested structs, pointers to structs
+// and collections of structs. See [DescribeType] for details.
+func Describe[T any]() []FieldInfo { return DescribeType(reflect.TypeFor[T]()) }
+
+// DescribeType returns the description of every exported field of typ, which must be a struct type
+// or a pointer to a struct type, or nil otherwise.
+//
+// Fields are listed in declaration order, each struct field being followed by the fields
+// of its nested struct, if any. Recursive types are described once in each branch.
+func DescribeType(typ reflect.Type) []FieldInfo { return defaultParser.DescribeType(typ) }
+
+// DescribeType returns the description of every exported field of typ, see [DescribeType] for details.
+func (p *Parser) DescribeType(typ reflect.Type) []FieldInfo {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return (&applier{Parser: p}).describeStruct(typ, "", map[reflect.Type]bool{})
+}
+
+func (a *applier) describeStruct(typ reflect.Type, prefix string, visiting map[reflect.Type]bool) []FieldInfo {
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	var infos []FieldInfo
+
+	for i := range typ.NumField() {
+		typeField := typ.Field(i)
+		if !typeField.IsExported() {
+			continue
+		}
+
+		info := a.describeField(typeField)
+		info.Path = prefix + typeField.Name
+		infos = append(infos, info)
+
+		// Describe the nested structs, "*" designating collection elements.
+		nested, path := typeField.Type, info.Path
+
+		for {
+			switch nested.Kind() {
+			case reflect.Pointer:
+				nested = nested.Elem()
+
+				continue
+			case reflect.Array, reflect.Map, reflect.Slice:
+				nested = nested.Elem()
+				path += ".*"
+
+				continue
+			}
+
+			break
+		}
+
+		if nested.Kind() == reflect.Struct && !a.hasParser(nested) && !visiting[nested] {
+			infos = append(infos, a.describeStruct(nested, path+".", visiting)...)
+		}
+	}
+
+	return infos
+}
+
+func (a *applier) describeField(typeField reflect.StructField) FieldInfo {
+	info := FieldInfo{
+		Type: typeField.Type,
+		Tag:  typeField.Tag,
+		Doc:  typeField.Tag.Get(docTagName),
+	}
+
+	info.Default, info.HasDefault = a.lookupDefault(typeField.Tag)
+	_, info.Dynamic = typeField.Tag.Lookup(conditionalTagName)
+
+	if !info.HasDefault {
+		if value, ok := typeField.Tag.Lookup(jsonTagName); ok {
+			info.Default, info.HasDefault = value, true
+
+			if !info.Dynamic {
+				info.Value, info.Err = describedValue(a.parseJSON(reflect.New(typeField.Type).Elem(), value))
+			}
+		}
+
+		return info
+	}
+
+	if info.Dynamic || tags.IsDynamic(info.Default) {
+		info.Dynamic = true
+
+		return info
+	}
+
+	value, err := a.interpolate(tags.Unescape(info.Default))
+	if err != nil {
+		info.Err = err
+
+		return info
+	}
+
+	app := &applier{Parser: a.Parser}
+
+	info.Value, info.Err = describedValue(app.parse(reflect.New(typeField.Type).Elem(), value, true, true))
+
+	if !app.currentTime.IsZero() {
+		// Relative times are resolved when applying defaults.
+		info.Value, info.Dynamic = nil, true
+	}
+
+	return info
+}
+
+func describedValue(value reflect.Value, errs []error) (any, error) {
+	if len(errs) > 0 || !value.IsValid() {
+		return nil, errors.Join(errs...)
+	}
+
+	return value.Interface(), nil
+}
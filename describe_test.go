@@ -0,0 +1,103 @@
+package defaults_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type describedBackend struct {
+	Host string `default:"localhost" doc:"Host name"`
+}
+
+type describedConfig struct {
+	Name     string        `default:"app"        doc:"Application name"`
+	Timeout  time.Duration `default:"5s"`
+	Count    int           `default:"abc"`
+	URL      string        `default:"http://${Name}"`
+	Ports    []int         `default_json:"[80,443]"`
+	Backend  describedBackend
+	Backends map[string]*describedBackend
+	Next     *describedConfig
+	hidden   string `default:"hidden"`
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	infos := defaults.Describe[describedConfig]()
+
+	paths := make([]string, 0, len(infos))
+	byPath := map[string]defaults.FieldInfo{}
+
+	for _, info := range infos {
+		paths = append(paths, info.Path)
+		byPath[info.Path] = info
+	}
+
+	expectedPaths := []string{
+		"Name", "Timeout", "Count", "URL", "Ports",
+		"Backend", "Backend.Host",
+		"Backends", "Backends.*.Host",
+		"Next",
+	}
+
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("expected paths %v, got %v", expectedPaths, paths)
+	}
+
+	if name := byPath["Name"]; name.Value != "app" || name.Doc != "Application name" || !name.HasDefault {
+		t.Errorf("unexpected Name description: %+v", name)
+	}
+
+	if timeout := byPath["Timeout"]; timeout.Value != 5*time.Second || timeout.Type != reflect.TypeFor[time.Duration]() {
+		t.Errorf("unexpected Timeout description: %+v", timeout)
+	}
+
+	if count := byPath["Count"]; count.Err == nil || count.Value != nil || count.Default != "abc" {
+		t.Errorf("unexpected Count description: %+v", count)
+	}
+
+	if url := byPath["URL"]; !url.Dynamic || url.Value != nil {
+		t.Errorf("unexpected URL description: %+v", url)
+	}
+
+	if ports := byPath["Ports"]; !reflect.DeepEqual(ports.Value, []int{80, 443}) {
+		t.Errorf("unexpected Ports description: %+v", ports)
+	}
+
+	if host := byPath["Backends.*.Host"]; host.Value != "localhost" || host.Doc != "Host name" {
+		t.Errorf("unexpected Backends.*.Host description: %+v", host)
+	}
+
+	if backend := byPath["Backend"]; backend.HasDefault || backend.Value != nil {
+		t.Errorf("unexpected Backend description: %+v", backend)
+	}
+
+	if infos := defaults.Describe[int](); infos != nil {
+		t.Errorf("expected no description for a non-struct type, got %+v", infos)
+	}
+}
+
+type describedServers struct {
+	Servers []struct {
+		Port int `default:"abc"`
+	}
+}
+
+func TestDescribePathsMatchCheck(t *testing.T) {
+	t.Parallel()
+
+	// Paths use the same wildcard for collection elements as the errors of CheckType.
+	infos := defaults.Describe[describedServers]()
+	if path := infos[len(infos)-1].Path; path != "Servers.*.Port" {
+		t.Errorf("expected path Servers.*.Port, got %s", path)
+	}
+
+	if err := defaults.Check[describedServers](); err == nil || !strings.HasPrefix(err.Error(), "Servers.*.Port: ") {
+		t.Errorf("expected an error prefixed with Servers.*.Port, got %v", err)
+	}
+}
@@ -14,6 +14,22 @@ var (
 	// ErrUnsupportedType is returned when the target type is not supported.
 	ErrUnsupportedType = errors.New("unsupported type for defaults")
 
+	// ErrUnknownReference is returned when a default value references a field that does not exist
+	// (or an environment variable that is not set).
+	ErrUnknownReference = errors.New("unknown field reference")
+
+	// ErrReferenceCycle is returned when default values reference each other in a cycle.
+	ErrReferenceCycle = errors.New("cycle in field references")
+
+	// ErrUnknownProvider is returned when a default value references a provider that is not registered.
+	ErrUnknownProvider = errors.New("unknown default value provider")
+
+	// ErrInvalidValue is returned when a value does not satisfy its validation tags.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrRequired is returned when a field with the "required" tag is still zero once defaults are applied.
+	ErrRequired = errors.New("value is required")
+
 	// ErrMustBePointerToAStruct is returned when the target is not a pointer to a struct.
 	ErrMustBePointerToAStruct = errors.New("target must be a pointer to a struct")
 )
@@ -26,18 +42,22 @@ func (e fieldError) Unwrap() error {
 	return e.err
 }
 
+// addErrorsPrefixes returns errs prefixed with the path of their field.
+// A new slice is returned, as errs may be shared by compiled plans.
 func addErrorsPrefixes(prefix string, errs []error) []error {
+	result := make([]error, len(errs))
+
 	for i, err := range errs {
 		var fErr fieldError
 		if errors.As(err, &fErr) {
 			fErr.field = prefix + "." + fErr.field
-			errs[i] = fErr
+			result[i] = fErr
 
 			continue
 		}
 
-		errs[i] = fieldError{field: prefix, err: err}
+		result[i] = fieldError{field: prefix, err: err}
 	}
 
-	return errs
+	return result
 }
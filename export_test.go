@@ -0,0 +1,8 @@
+package defaults
+
+// WithoutPlanCache disables the cache of compiled plans, to measure its gain in benchmarks.
+func WithoutPlanCache() Option {
+	return func(p *Parser) {
+		p.noPlanCache = true
+	}
+}
@@ -0,0 +1,150 @@
+package defaults
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// GeneratedDefaulter is implemented by the types whose defaults have been compiled
+// into methods by the defaultsgen command (see [github.com/willoma/defaults/cmd/defaultsgen]).
+//
+// SetDefaults sets all the tagged defaults, overwriting existing values,
+// and CompleteDefaults sets the tagged defaults of zero fields only.
+// Both methods also apply the defaults of nested structs.
+//
+// When a type implements GeneratedDefaulter, [Set] and [Complete] call these methods
+// instead of parsing the tags with reflection, as long as the parser reads the "default" tag
+// without profiles or pointer mode, which the generated methods do not know about.
+// Types are also parsed with reflection when a parser registered with [RegisterParser] or [WithParser]
+// applies to one of their fields, or to the fields of their nested structs and collections.
+// In any case, its SetDefaults method is not called as a [Defaulter] hook.
+//
+// Methods promoted from an embedded field only apply the defaults of this field:
+// structs embedding a type that implements GeneratedDefaulter are parsed with reflection.
+type GeneratedDefaulter interface {
+	SetDefaults()
+	CompleteDefaults()
+}
+
+// generatedTypes caches whether struct types have their own generated methods.
+//
+//nolint:gochecknoglobals // This only depends on the types, so it is shared by all parsers.
+var generatedTypes sync.Map
+
+// hasGenerated reports whether typ, a struct type, has its own generated methods,
+// rather than methods promoted from an embedded field.
+func hasGenerated(typ reflect.Type) bool {
+	if cached, ok := generatedTypes.Load(typ); ok {
+		result, _ := cached.(bool)
+
+		return result
+	}
+
+	generatedType := reflect.TypeFor[GeneratedDefaulter]()
+	result := reflect.PointerTo(typ).Implements(generatedType)
+
+	for i := 0; result && i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous {
+			result = !field.Type.Implements(generatedType) && !reflect.PointerTo(field.Type).Implements(generatedType)
+		}
+	}
+
+	generatedTypes.Store(typ, result)
+
+	return result
+}
+
+// fieldTypes caches the types reachable from the fields of struct types.
+//
+//nolint:gochecknoglobals // This only depends on the types, so it is shared by all parsers.
+var fieldTypes sync.Map
+
+// reachableTypes returns the types of the fields of typ, a struct type,
+// and of their elements, keys and nested fields, recursively.
+func reachableTypes(typ reflect.Type) map[reflect.Type]bool {
+	if cached, ok := fieldTypes.Load(typ); ok {
+		result, _ := cached.(map[reflect.Type]bool)
+
+		return result
+	}
+
+	result := map[reflect.Type]bool{}
+
+	var walk func(reflect.Type)
+
+	walk = func(typ reflect.Type) {
+		if result[typ] {
+			return
+		}
+
+		result[typ] = true
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			for i := range typ.NumField() {
+				walk(typ.Field(i).Type)
+			}
+		case reflect.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+			walk(typ.Elem())
+		default:
+		}
+	}
+
+	walk(typ)
+	fieldTypes.Store(typ, result)
+
+	return result
+}
+
+// usesGenerated reports whether generated methods may be called instead of parsing the tags of typ.
+func (p *Parser) usesGenerated(typ reflect.Type) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.profiles) > 0 ||
+		p.pointerMode != PointerSkip ||
+		!slices.Equal(p.tagNames, []string{defaultTagName}) {
+		return false
+	}
+
+	if len(p.parsers) == 0 {
+		return true
+	}
+
+	// Registered parsers only matter if they apply to the fields of typ.
+	types := reachableTypes(typ)
+	for parserType := range p.parsers {
+		if types[parserType] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// callGenerated calls the generated methods of target, a pointer to a struct,
+// and reports whether they have been called.
+func (a *applier) callGenerated(target any, overwrite bool) bool {
+	if a.validating || a.checking {
+		return false
+	}
+
+	typ := reflect.TypeOf(target).Elem()
+
+	generated, ok := target.(GeneratedDefaulter)
+	if !ok || !hasGenerated(typ) || !a.usesGenerated(typ) {
+		return false
+	}
+
+	if overwrite {
+		generated.SetDefaults()
+	} else {
+		generated.CompleteDefaults()
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package defaults_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+	"github.com/willoma/defaults/internal/testdata/generated"
+)
+
+type embeddingConfig struct {
+	generated.Backend
+
+	Name string `default:"embedding"`
+}
+
+//nolint:paralleltest // AllocsPerRun cannot run in parallel tests.
+func TestGenerated(t *testing.T) {
+	config := generated.Config{Name: "custom"}
+	if err := defaults.Complete(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	retries := 3
+	expected := generated.Config{
+		Name:    "custom",
+		Timeout: 5 * time.Second,
+		Hosts:   []string{"a", "b"},
+		Limits:  map[string]int{"read": 10, "write": 5},
+		Retries: &retries,
+		Backend: generated.Backend{Host: "localhost", Port: 80},
+	}
+
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+
+	if err := defaults.Set(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Name = "app"
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+
+	// Generated methods and reflection apply the same defaults:
+	// generated methods are not called when profiles are active.
+	var reflective generated.Config
+	if err := defaults.Set(&reflective, defaults.WithProfiles("unused")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(reflective, config) {
+		t.Errorf("generated methods and reflection differ: %+v, %+v", config, reflective)
+	}
+
+	// Generated methods do not use reflection.
+	direct := testing.AllocsPerRun(100, func() { config.SetDefaults() })
+	if allocs := testing.AllocsPerRun(100, func() { _ = defaults.Set(&config) }); allocs != direct {
+		t.Errorf("expected %v allocations, like the generated method, got %v", direct, allocs)
+	}
+
+	// Generated methods do not know about other tags, the tags are parsed instead.
+	var otherTag generated.Config
+	if err := defaults.Set(&otherTag, defaults.WithTagName("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(otherTag, generated.Config{}) {
+		t.Errorf("expected zero value with another tag, got %+v", otherTag)
+	}
+
+	// Parsers for types that generated.Config does not use do not prevent calling generated methods.
+	unrelated := defaults.NewParser(defaults.WithParser(parseCents))
+	if allocs := testing.AllocsPerRun(100, func() { _ = unrelated.Set(&config) }); allocs != direct {
+		t.Errorf("expected %v allocations with an unrelated parser, got %v", direct, allocs)
+	}
+
+	// Parsers for the types of its fields do.
+	related := defaults.NewParser(defaults.WithParser(func(string) (time.Duration, error) { return time.Minute, nil }))
+
+	var custom generated.Config
+	if err := related.Set(&custom); err != nil {
+		t.Fatal(err)
+	}
+
+	if custom.Timeout != time.Minute {
+		t.Errorf("expected the registered parser to be used, got %+v", custom)
+	}
+
+	// Promoted methods only apply the defaults of the embedded struct.
+	var embedding embeddingConfig
+	if err := defaults.Set(&embedding); err != nil {
+		t.Fatal(err)
+	}
+
+	if embedding.Name != "embedding" || embedding.Port != 80 {
+		t.Errorf("expected defaults of both the embedding and the embedded structs, got %+v", embedding)
+	}
+}
@@ -0,0 +1,46 @@
+package defaults
+
+import "reflect"
+
+// Defaulter is implemented by types that compute some of their defaults in code,
+// for instance derived values or defaults that cannot be expressed as a string.
+//
+// SetDefaults is called on every visited struct before its tagged defaults are applied.
+type Defaulter interface {
+	SetDefaults()
+}
+
+// FallibleDefaulter is like [Defaulter], for SetDefaults methods that may fail.
+type FallibleDefaulter interface {
+	SetDefaults() error
+}
+
+// AfterDefaulter is implemented by types that need to adjust their values
+// once the tagged defaults have been applied.
+//
+// AfterDefaults is called on every visited struct after its tagged defaults,
+// including those of its nested structs, have been applied.
+type AfterDefaulter interface {
+	AfterDefaults() error
+}
+
+func callBeforeHook(target reflect.Value) error {
+	switch hook := target.Addr().Interface().(type) {
+	case GeneratedDefaulter:
+		// Generated methods are not hooks, they are only called instead of parsing the tags.
+	case Defaulter:
+		hook.SetDefaults()
+	case FallibleDefaulter:
+		return hook.SetDefaults()
+	}
+
+	return nil
+}
+
+func callAfterHook(target reflect.Value) error {
+	if hook, ok := target.Addr().Interface().(AfterDefaulter); ok {
+		return hook.AfterDefaults()
+	}
+
+	return nil
+}
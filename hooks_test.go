@@ -0,0 +1,81 @@
+package defaults_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+var errNoEndpoint = errors.New("no endpoint")
+
+type hookedServer struct {
+	Host     string `default:"localhost"`
+	Port     int    `default:"8080"`
+	Endpoint string
+	Trace    []string
+}
+
+func (s *hookedServer) SetDefaults() {
+	s.Trace = append(s.Trace, "before:"+s.Host)
+}
+
+func (s *hookedServer) AfterDefaults() error {
+	s.Trace = append(s.Trace, "after:"+s.Host)
+	s.Endpoint = s.Host + ":" + strconv.Itoa(s.Port)
+
+	if s.Port == 0 {
+		return errNoEndpoint
+	}
+
+	return nil
+}
+
+type hookedConfig struct {
+	Server hookedServer
+	Name   string `default:"app"`
+}
+
+func (c *hookedConfig) SetDefaults() error {
+	if c.Name == "fail" {
+		return errNoEndpoint
+	}
+
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	var value hookedConfig
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Server.Endpoint != "localhost:8080" {
+		t.Errorf("wrong value for Server.Endpoint: %q", value.Server.Endpoint)
+	}
+
+	if !slices.Equal(value.Server.Trace, []string{"before:", "after:localhost"}) {
+		t.Errorf("wrong hooks order: %q", value.Server.Trace)
+	}
+
+	failing := hookedConfig{Name: "fail"}
+	if err := defaults.Complete(&failing); !errors.Is(err, errNoEndpoint) {
+		t.Errorf("expected errNoEndpoint, got %v", err)
+	}
+
+	nested := struct{ Inner hookedConfig }{Inner: hookedConfig{Name: "fail"}}
+
+	err := defaults.Complete(&nested)
+	if !errors.Is(err, errNoEndpoint) {
+		t.Fatalf("expected errNoEndpoint, got %v", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "Inner: ") {
+		t.Errorf("wrong error prefix: %q", err.Error())
+	}
+}
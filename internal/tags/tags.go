@@ -0,0 +1,214 @@
+// Package tags holds the grammar of the struct tags and default values read by [github.com/willoma/defaults].
+//
+// It is shared by the defaults package, its analyzer and the defaultsgen command,
+// so that they agree on which tags define defaults and on which default values are dynamic.
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+// Names of the struct tags read by the defaults package.
+const (
+	Default     = "default"
+	JSON        = "default_json"
+	Conditional = "default_if"
+	PointerMode = "default_ptr"
+)
+
+// ErrUnterminated is returned by [Expand] when a braced reference is not closed.
+var ErrUnterminated = errors.New("unterminated reference")
+
+// Keys returns the keys of the key:"value" pairs in tag, following the conventional struct tag syntax.
+func Keys(tag reflect.StructTag) []string {
+	var keys []string
+
+	for tag != "" {
+		// Skip leading spaces, then scan the key up to the colon.
+		tag = reflect.StructTag(strings.TrimLeft(string(tag), " "))
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+
+		keys = append(keys, string(tag[:i]))
+		tag = tag[i+1:]
+
+		// Scan the quoted value, then skip it.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+
+			i++
+		}
+
+		if i >= len(tag) {
+			break
+		}
+
+		tag = tag[i+1:]
+	}
+
+	return keys
+}
+
+// IsDefault reports whether key is one of names, or a profile variant of one of them.
+func IsDefault(key string, names []string) bool {
+	for _, name := range names {
+		if key == name || strings.HasPrefix(key, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Provider parses a "@name" or "@name:arg" provider reference,
+// and reports whether value is a provider reference; "@@" escapes a literal "@".
+func Provider(value string) (name, arg string, ok bool) {
+	if !strings.HasPrefix(value, "@") || strings.HasPrefix(value, "@@") {
+		return "", "", false
+	}
+
+	name, arg, _ = strings.Cut(value[1:], ":")
+
+	return name, arg, true
+}
+
+// Unescape returns value with an escaped leading "@@" replaced with "@".
+// It must only be called on values that are not provider references.
+func Unescape(value string) string {
+	return strings.TrimPrefix(value, "@")
+}
+
+// Reference is a "$NAME", "${name}" or "${name:-fallback}" reference in a default value.
+type Reference struct {
+	Name        string
+	Fallback    string
+	HasFallback bool
+	Braced      bool
+}
+
+// Expand replaces each reference in value with the result of resolve.
+// "$$" is replaced with a literal "$", and a "$" that does not start a reference is kept as-is.
+func Expand(value string, resolve func(ref Reference) (string, error)) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var result strings.Builder
+
+	for {
+		start := strings.IndexByte(value, '$')
+		if start < 0 {
+			result.WriteString(value)
+
+			return result.String(), nil
+		}
+
+		result.WriteString(value[:start])
+		value = value[start+1:]
+
+		var ref Reference
+
+		switch {
+		case strings.HasPrefix(value, "$"):
+			result.WriteByte('$')
+			value = value[1:]
+
+			continue
+
+		case strings.HasPrefix(value, "{"):
+			end := strings.IndexByte(value, '}')
+			if end < 0 {
+				return "", fmt.Errorf("%w in %q", ErrUnterminated, value)
+			}
+
+			ref.Braced = true
+			ref.Name, ref.Fallback, ref.HasFallback = strings.Cut(value[1:end], ":-")
+			value = value[end+1:]
+
+		default:
+			length := envNameLength(value)
+			if length == 0 {
+				result.WriteByte('$')
+
+				continue
+			}
+
+			ref.Name = value[:length]
+			value = value[length:]
+		}
+
+		resolved, err := resolve(ref)
+		if err != nil {
+			return "", err
+		}
+
+		result.WriteString(resolved)
+	}
+}
+
+// envNameLength returns the length of the environment variable name at the start of value.
+func envNameLength(value string) int {
+	for i, char := range value {
+		if char == '_' || ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || (i > 0 && '0' <= char && char <= '9') {
+			continue
+		}
+
+		return i
+	}
+
+	return len(value)
+}
+
+// IsDynamic reports whether the default value depends on the context it is applied in,
+// that is if it is a provider reference or if it contains references.
+// Invalid values are not dynamic, so that parsing them reports their errors.
+//
+// Relative times are not detected, since they depend on the type of the field.
+func IsDynamic(value string) bool {
+	if _, _, ok := Provider(value); ok {
+		return true
+	}
+
+	var dynamic bool
+
+	//nolint:errcheck // Unterminated references are reported when parsing the value.
+	_, _ = Expand(Unescape(value), func(Reference) (string, error) {
+		dynamic = true
+
+		return "", nil
+	})
+
+	return dynamic
+}
+
+// EmbeddedName returns the name of an embedded field of type expr, which is the name of its type.
+func EmbeddedName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return EmbeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	case *ast.IndexExpr:
+		return EmbeddedName(expr.X)
+	case *ast.IndexListExpr:
+		return EmbeddedName(expr.X)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,74 @@
+package tags_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := tags.Keys(`default:"a \"b\" c" default.prod:"d"  json:"e,omitempty" broken`)
+	if expected := []string{"default", "default.prod", "json"}; !slices.Equal(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	if !tags.IsDefault("default.prod", []string{"default"}) || tags.IsDefault("defaults", []string{"default"}) {
+		t.Error("wrong classification of default tags")
+	}
+}
+
+func TestIsDynamic(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]bool{
+		"plain":        false,
+		"@@literal":    false,
+		"price: $$5":   false,
+		"lone $ sign":  false,
+		"${unclosed":   false,
+		"@hostname":    true,
+		"@random:32":   true,
+		"$HOME/config": true,
+		"${Name}":      true,
+		"@@${Name}":    true,
+	} {
+		if dynamic := tags.IsDynamic(value); dynamic != expected {
+			t.Errorf("IsDynamic(%q): expected %v, got %v", value, expected, dynamic)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	result, err := tags.Expand("$A-${b.c:-d}-$$-$", func(ref tags.Reference) (string, error) {
+		if ref.Braced {
+			return ref.Name + "|" + ref.Fallback, nil
+		}
+
+		return ref.Name, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != "A-b.c|d-$-$" {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	if _, err := tags.Expand("${unclosed", nil); !errors.Is(err, tags.ErrUnterminated) {
+		t.Errorf("expected ErrUnterminated, got %v", err)
+	}
+
+	if name, arg, ok := tags.Provider("@random:32"); !ok || name != "random" || arg != "32" {
+		t.Errorf("unexpected provider %q %q %v", name, arg, ok)
+	}
+
+	if tags.Unescape("@@literal") != "@literal" {
+		t.Error("escaped @ not unescaped")
+	}
+}
@@ -0,0 +1,23 @@
+// Package generated holds types with methods generated by defaultsgen,
+// used to test that the generated methods and reflection apply the same defaults.
+package generated
+
+import "time"
+
+//go:generate go run github.com/willoma/defaults/cmd/defaultsgen -type Config
+
+// Backend is a nested struct with its own generated methods.
+type Backend struct {
+	Host string `default:"localhost"`
+	Port int    `default:"80"`
+}
+
+// Config is a struct with generated methods.
+type Config struct {
+	Name    string         `default:"app"`
+	Timeout time.Duration  `default:"5s"`
+	Hosts   []string       `default:"a,b"`
+	Limits  map[string]int `default:"read:10,write:5"`
+	Retries *int           `default:"3"`
+	Backend Backend
+}
@@ -0,0 +1,331 @@
+package defaults
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// WithLookupEnv configures the function used to look up environment variables
+// referenced in default values, instead of [os.LookupEnv].
+// It is mostly useful in tests, to provide a fake environment.
+func WithLookupEnv(lookup func(name string) (string, bool)) Option {
+	return func(p *Parser) {
+		p.lookupEnv = lookup
+	}
+}
+
+// interpolate expands the references to other fields and to environment variables in value.
+func (a *applier) interpolate(value string) (string, error) {
+	return expandReferences(value, a.resolveReference)
+}
+
+// expandReferences replaces each reference in value with the result of resolve, see [tags.Expand].
+func expandReferences(value string, resolve func(ref tags.Reference) (string, error)) (string, error) {
+	result, err := tags.Expand(value, resolve)
+	if errors.Is(err, tags.ErrUnterminated) {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	return result, err
+}
+
+// references returns the paths of the fields possibly referenced in value.
+func references(value string) []string {
+	var paths []string
+
+	//nolint:errcheck // The resolver never fails, unterminated references are reported when interpolating.
+	_, _ = expandReferences(value, func(ref tags.Reference) (string, error) {
+		if ref.Braced {
+			paths = append(paths, ref.Name)
+		}
+
+		return "", nil
+	})
+
+	return paths
+}
+
+// resolveReference resolves a reference in a default value.
+//
+// Braced references designate fields when their path matches a field,
+// and environment variables otherwise, if their name is written like an environment variable
+// (see [isEnvName]): other names can only be field paths, and are an error when they match no field.
+// A braced reference that matches neither a field nor a set environment variable,
+// and has no fallback, is an error too.
+// Unbraced references designate environment variables, and are kept as written when unset,
+// so that default values written before references were supported keep their meaning.
+func (a *applier) resolveReference(ref tags.Reference) (string, error) {
+	if ref.Braced {
+		value, found, err := a.lookupReference(ref.Name)
+		if err != nil {
+			return "", err
+		}
+
+		if found {
+			if value == "" && ref.HasFallback {
+				return ref.Fallback, nil
+			}
+
+			return value, nil
+		}
+
+		if !isEnvName(ref.Name) {
+			return "", fmt.Errorf("%w: %q", ErrUnknownReference, ref.Name)
+		}
+	}
+
+	if value, ok := a.lookupEnv(ref.Name); ok && (value != "" || !ref.HasFallback) {
+		return value, nil
+	}
+
+	if ref.HasFallback {
+		return ref.Fallback, nil
+	}
+
+	if ref.Braced {
+		return "", fmt.Errorf("%w: %q", ErrUnknownReference, ref.Name)
+	}
+
+	return "$" + ref.Name, nil
+}
+
+// isEnvName reports whether name is written like an environment variable,
+// with upper-case letters, digits and underscores only, rather than like a field path.
+func isEnvName(name string) bool {
+	return name != "" && !strings.ContainsFunc(name, func(char rune) bool {
+		return char != '_' && !('A' <= char && char <= 'Z') && !('0' <= char && char <= '9')
+	})
+}
+
+// lookupReference returns the formatted value of the field at path,
+// looked up in the struct being parsed, then in its ancestors.
+// It reports whether the path designates a field.
+func (a *applier) lookupReference(path string) (string, bool, error) {
+	value, found, err := a.lookupField(path)
+
+	return formatValue(value), found, err
+}
+
+// lookupField returns the field at path, looked up in the struct being parsed, then in its ancestors.
+// It reports whether the path designates a field.
+// If a nil pointer is found along the path, the returned value is invalid.
+func (a *applier) lookupField(path string) (reflect.Value, bool, error) {
+	segments := strings.Split(path, ".")
+
+	for i := len(a.scopes) - 1; i >= 0; i-- {
+		value, found, err := lookupPath(a.scopes[i], segments)
+		if err != nil {
+			return reflect.Value{}, true, fmt.Errorf("%w: %q", err, path)
+		}
+
+		if found {
+			return value, true, nil
+		}
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+// lookupPath returns the field of scope designated by segments.
+// It reports whether the first segment designates a field of scope.
+// If a nil pointer is found along the path, the returned value is invalid.
+func lookupPath(scope reflect.Value, segments []string) (reflect.Value, bool, error) {
+	current := scope
+
+	for i, segment := range segments {
+		for current.Kind() == reflect.Pointer {
+			if current.IsNil() {
+				return reflect.Value{}, true, nil
+			}
+
+			current = current.Elem()
+		}
+
+		var (
+			typeField reflect.StructField
+			ok        bool
+		)
+
+		if current.Kind() == reflect.Struct {
+			typeField, ok = current.Type().FieldByName(segment)
+		}
+
+		if !ok || !typeField.IsExported() {
+			if i == 0 {
+				return reflect.Value{}, false, nil
+			}
+
+			return reflect.Value{}, true, ErrUnknownReference
+		}
+
+		field, err := current.FieldByIndexErr(typeField.Index)
+		if err != nil {
+			return reflect.Value{}, true, nil //nolint:nilerr // Nil embedded pointers are zero values.
+		}
+
+		current = field
+	}
+
+	return current, true, nil
+}
+
+// formatValue formats a referenced value as a string.
+// Values implementing [encoding.TextMarshaler] are formatted with their MarshalText method.
+func formatValue(value reflect.Value) string {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return ""
+	}
+
+	marshaler, ok := value.Interface().(encoding.TextMarshaler)
+	if !ok && value.CanAddr() {
+		marshaler, ok = value.Addr().Interface().(encoding.TextMarshaler)
+	}
+
+	if ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// computeFieldOrder returns the indexes of the exported fields of typ,
+// ordered so that fields are parsed after the sibling fields their defaults reference.
+func (a *applier) computeFieldOrder(typ reflect.Type) ([]int, error) {
+	var (
+		exported     []int
+		dependencies = map[int][]int{}
+	)
+
+	for i := range typ.NumField() {
+		typeField := typ.Field(i)
+		if !typeField.IsExported() {
+			continue
+		}
+
+		exported = append(exported, i)
+
+		for _, name := range a.fieldReferences(typeField, map[reflect.Type]bool{typ: true}) {
+			if sibling, ok := typ.FieldByName(name); ok && sibling.IsExported() && len(sibling.Index) == 1 {
+				dependencies[i] = append(dependencies[i], sibling.Index[0])
+			}
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return exported, nil
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		order []int
+		state = map[int]int{}
+		path  []string
+		visit func(i int) error
+	)
+
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s -> %s", ErrReferenceCycle, strings.Join(path, " -> "), typ.Field(i).Name)
+		}
+
+		state[i] = visiting
+		path = append(path, typ.Field(i).Name)
+
+		for _, dependency := range dependencies[i] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, i)
+
+		return nil
+	}
+
+	for _, i := range exported {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// fieldReferences returns the first segments of the paths referenced by the default values of typeField
+// and by the defaults of the nested structs it contains, when they do not designate fields of these nested structs.
+func (a *applier) fieldReferences(typeField reflect.StructField, visited map[reflect.Type]bool) []string {
+	var names []string
+
+	var paths []string
+
+	if value, ok := a.lookupDefault(typeField.Tag); ok {
+		paths = append(paths, references(value)...)
+	}
+
+	if tag, ok := typeField.Tag.Lookup(conditionalTagName); ok {
+		// Invalid rules are reported when parsing the field.
+		conditions, _ := parseConditions(tag)
+		for _, cond := range conditions {
+			paths = append(paths, cond.path)
+			paths = append(paths, references(cond.result)...)
+		}
+	}
+
+	for _, path := range paths {
+		name, _, _ := strings.Cut(path, ".")
+		names = append(names, name)
+	}
+
+	nested := typeField.Type
+	for slices.Contains([]reflect.Kind{reflect.Array, reflect.Map, reflect.Pointer, reflect.Slice}, nested.Kind()) {
+		nested = nested.Elem()
+	}
+
+	if nested.Kind() != reflect.Struct || visited[nested] || a.hasParser(nested) {
+		return names
+	}
+
+	visited[nested] = true
+	defer delete(visited, nested)
+
+	for i := range nested.NumField() {
+		nestedField := nested.Field(i)
+		if !nestedField.IsExported() {
+			continue
+		}
+
+		for _, name := range a.fieldReferences(nestedField, visited) {
+			if _, ok := nested.FieldByName(name); !ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
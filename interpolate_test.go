@@ -0,0 +1,140 @@
+package defaults_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type interpolatedStorage struct {
+	DataDir  string `default:"${BaseDir}/data"`
+	CacheDir string `default:"${DataDir}/cache"`
+}
+
+func TestInterpolation(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		URL     string `default:"http://${Host}:${Port}"`
+		Storage interpolatedStorage
+		Host    string        `default:"localhost"`
+		Port    int           `default:"8080"`
+		BaseDir string        `default:"/var/lib/app"`
+		Timeout time.Duration `default:"${Backoff}"`
+		Backoff time.Duration `default:"1m30s"`
+		Label   string        `default:"${Storage.CacheDir}"`
+	}
+
+	value := config{Port: 9090}
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.URL != "http://localhost:9090" {
+		t.Errorf("wrong value for URL: %q", value.URL)
+	}
+
+	if value.Storage.DataDir != "/var/lib/app/data" || value.Storage.CacheDir != "/var/lib/app/data/cache" {
+		t.Errorf("wrong value for Storage: %+v", value.Storage)
+	}
+
+	if value.Timeout != 90*time.Second {
+		t.Errorf("wrong value for Timeout: %s", value.Timeout)
+	}
+
+	if value.Label != "/var/lib/app/data/cache" {
+		t.Errorf("wrong value for Label: %q", value.Label)
+	}
+
+	var cycle struct {
+		A string `default:"${B}"`
+		B string `default:"${A}"`
+	}
+
+	if err := defaults.Set(&cycle); !errors.Is(err, defaults.ErrReferenceCycle) {
+		t.Errorf("expected ErrReferenceCycle, got %v", err)
+	}
+
+	var unknown struct {
+		A string `default:"${Missing}"`
+	}
+
+	err := defaults.Set(&unknown)
+	if !errors.Is(err, defaults.ErrUnknownReference) {
+		t.Fatalf("expected ErrUnknownReference, got %v", err)
+	}
+
+	if err.Error() != `A: unknown field reference: "Missing"` {
+		t.Errorf("wrong error message: %q", err.Error())
+	}
+}
+
+func TestEnvironmentExpansion(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"HOME": "/home/willow", "EMPTY": "", "Host": "env-host", "Hots": "env-host"}
+	parser := defaults.NewParser(defaults.WithLookupEnv(func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	}))
+
+	var value struct {
+		Cache    string `default:"$HOME/.cache/app"`
+		Socket   string `default:"${XDG_RUNTIME_DIR:-/tmp}/app.sock"`
+		Empty    string `default:"${EMPTY:-fallback}"`
+		Price    string `default:"$$5 or $"`
+		Unset    string `default:"[$UNSET]"`
+		Host     string `default:"field-host"`
+		Endpoint string `default:"${Host}"`
+	}
+
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Cache != "/home/willow/.cache/app" {
+		t.Errorf("wrong value for Cache: %q", value.Cache)
+	}
+
+	if value.Socket != "/tmp/app.sock" {
+		t.Errorf("wrong value for Socket: %q", value.Socket)
+	}
+
+	if value.Empty != "fallback" {
+		t.Errorf("wrong value for Empty: %q", value.Empty)
+	}
+
+	if value.Price != "$5 or $" {
+		t.Errorf("wrong value for Price: %q", value.Price)
+	}
+
+	if value.Unset != "[$UNSET]" {
+		t.Errorf("wrong value for Unset: %q", value.Unset)
+	}
+
+	if value.Endpoint != "field-host" {
+		t.Errorf("wrong value for Endpoint: %q", value.Endpoint)
+	}
+
+	var unset struct {
+		A string `default:"${UNSET}"`
+	}
+
+	if err := parser.Set(&unset); !errors.Is(err, defaults.ErrUnknownReference) {
+		t.Errorf("expected ErrUnknownReference, got %v", err)
+	}
+
+	// Names that are not written like environment variables are only field paths,
+	// so that a typo is not read from the environment.
+	var typo struct {
+		Host     string `default:"field-host"`
+		Endpoint string `default:"${Hots:-fallback}"`
+	}
+
+	if err := parser.Set(&typo); !errors.Is(err, defaults.ErrUnknownReference) {
+		t.Errorf("expected ErrUnknownReference, got %v", err)
+	}
+}
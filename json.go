@@ -0,0 +1,234 @@
+package defaults
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+const jsonTagName = tags.JSON
+
+// parseJSON decodes a JSON-encoded default value into a value of the type of target.
+//
+// The JSON document is decoded generically, then converted to the target type:
+// scalars are passed as strings to the usual parsers, which makes it possible
+// to use JSON strings for types like [time.Duration] or [fs.FileMode].
+func (a *applier) parseJSON(target reflect.Value, value string) (reflect.Value, []error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return reflect.Value{}, []error{fmt.Errorf("%w: %w", ErrInvalidFormat, err)}
+	}
+
+	// The default value must hold a single JSON value.
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return reflect.Value{}, []error{fmt.Errorf("%w: unexpected data after the JSON value", ErrInvalidFormat)}
+	}
+
+	return a.fromJSON(target.Type(), data)
+}
+
+//nolint:gocognit // Splitting this conversion would not make it more readable.
+func (a *applier) fromJSON(typ reflect.Type, data any) (reflect.Value, []error) {
+	if data == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	if !a.isComposite(typ) {
+		return a.fromJSONScalar(typ, data)
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		value, errs := a.fromJSON(typ.Elem(), data)
+		if len(errs) > 0 {
+			return reflect.Value{}, errs
+		}
+
+		result := reflect.New(typ.Elem())
+		result.Elem().Set(value)
+
+		return result, nil
+
+	case reflect.Array, reflect.Slice:
+		items, ok := data.([]any)
+		if !ok {
+			return reflect.Value{}, []error{fmt.Errorf("%w: expected a JSON array, got %T", ErrInvalidFormat, data)}
+		}
+
+		var result reflect.Value
+
+		if typ.Kind() == reflect.Slice {
+			result = reflect.MakeSlice(typ, len(items), len(items))
+		} else {
+			if typ.Len() != len(items) {
+				return reflect.Value{}, []error{
+					fmt.Errorf("%w: expected %d values, got %d", ErrInvalidFormat, typ.Len(), len(items)),
+				}
+			}
+
+			result = reflect.New(typ).Elem()
+		}
+
+		var errs []error
+
+		for i, item := range items {
+			value, err := a.fromJSON(typ.Elem(), item)
+			if len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(strconv.Itoa(i), err)...)
+
+				continue
+			}
+
+			result.Index(i).Set(value)
+		}
+
+		return result, errs
+
+	case reflect.Map:
+		object, ok := data.(map[string]any)
+		if !ok {
+			return reflect.Value{}, []error{fmt.Errorf("%w: expected a JSON object, got %T", ErrInvalidFormat, data)}
+		}
+
+		var errs []error
+
+		result := reflect.MakeMapWithSize(typ, len(object))
+
+		for key, item := range object {
+			keyValue, err := a.parse(reflect.New(typ.Key()).Elem(), key, true, true)
+			if len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(key, err)...)
+
+				continue
+			}
+
+			value, err := a.fromJSON(typ.Elem(), item)
+			if len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(key, err)...)
+
+				continue
+			}
+
+			result.SetMapIndex(keyValue, value)
+		}
+
+		return result, errs
+
+	default:
+		return a.fromJSONStruct(typ, data)
+	}
+}
+
+func (a *applier) fromJSONStruct(typ reflect.Type, data any) (reflect.Value, []error) {
+	object, ok := data.(map[string]any)
+	if !ok {
+		return reflect.Value{}, []error{fmt.Errorf("%w: expected a JSON object, got %T", ErrInvalidFormat, data)}
+	}
+
+	var errs []error
+
+	result := reflect.New(typ).Elem()
+
+	for key, item := range object {
+		typeField, ok := jsonField(typ, key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: unknown field %q", ErrInvalidFormat, key))
+
+			continue
+		}
+
+		value, err := a.fromJSON(typeField.Type, item)
+		if len(err) > 0 {
+			errs = append(errs, addErrorsPrefixes(typeField.Name, err)...)
+
+			continue
+		}
+
+		result.Field(typeField.Index[0]).Set(value)
+	}
+
+	if len(errs) > 0 {
+		return reflect.Value{}, errs
+	}
+
+	// Fields that are not in the JSON document receive their own defaults.
+	return a.parseStruct(result, false)
+}
+
+func (a *applier) fromJSONScalar(typ reflect.Type, data any) (reflect.Value, []error) {
+	var value string
+
+	switch data := data.(type) {
+	case string:
+		value = data
+	case json.Number:
+		value = data.String()
+	case bool:
+		value = strconv.FormatBool(data)
+	default:
+		return reflect.Value{}, []error{fmt.Errorf("%w: expected a JSON scalar, got %T", ErrInvalidFormat, data)}
+	}
+
+	return a.parse(reflect.New(typ).Elem(), value, true, true)
+}
+
+// jsonField returns the exported field of typ matching the key of a JSON object,
+// according to the "json" struct tag or to the field name.
+func jsonField(typ reflect.Type, key string) (reflect.StructField, bool) {
+	var (
+		candidate reflect.StructField
+		found     bool
+	)
+
+	for i := range typ.NumField() {
+		typeField := typ.Field(i)
+		if !typeField.IsExported() {
+			continue
+		}
+
+		name, _, skip := jsonName(typeField)
+		if skip {
+			continue
+		}
+
+		if name == key {
+			return typeField, true
+		}
+
+		if !found && strings.EqualFold(name, key) {
+			candidate = typeField
+			found = true
+		}
+	}
+
+	return candidate, found
+}
+
+// jsonName returns the name of typeField in JSON objects, according to its "json" struct tag,
+// and reports whether the name comes from the tag and whether the field is skipped.
+func jsonName(typeField reflect.StructField) (name string, tagged, skip bool) {
+	tag, ok := typeField.Tag.Lookup("json")
+	if !ok {
+		return typeField.Name, false, false
+	}
+
+	tagName, _, _ := strings.Cut(tag, ",")
+
+	switch tagName {
+	case "-":
+		return "", false, true
+	case "":
+		return typeField.Name, false, false
+	default:
+		return tagName, true, false
+	}
+}
@@ -0,0 +1,85 @@
+package defaults_test
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type jsonRoute struct {
+	Path    string        `json:"path"`
+	Timeout time.Duration `json:"timeout" default:"30s"`
+	Mode    fs.FileMode   `json:"mode"    default:"644"`
+}
+
+func TestJSONDefaults(t *testing.T) {
+	t.Parallel()
+
+	var value struct {
+		Routes  []jsonRoute        `default_json:"[{\"path\":\"/\",\"timeout\":\"5s\"},{\"Path\":\"/api\",\"mode\":\"600\"}]"`
+		Weights map[string][]int   `default_json:"{\"a\":[1,2],\"b\":[]}"`
+		Retry   *int               `default_json:"3"`
+		Enabled bool               `default_json:"true"`
+		Hosts   []net.IP           `default_json:"[\"10.0.0.1\",\"10.0.0.2\"]"`
+		Both    string             `default:"plain" default_json:"\"json\""`
+		Lookup  map[int]time.Month `default_json:"{\"1\":1,\"12\":12}"`
+	}
+
+	if err := defaults.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	expectedRoutes := []jsonRoute{
+		{Path: "/", Timeout: 5 * time.Second, Mode: 0o644},
+		{Path: "/api", Timeout: 30 * time.Second, Mode: 0o600},
+	}
+	if !slices.Equal(value.Routes, expectedRoutes) {
+		t.Errorf("wrong value for Routes: %+v", value.Routes)
+	}
+
+	if !maps.EqualFunc(value.Weights, map[string][]int{"a": {1, 2}, "b": {}}, slices.Equal) {
+		t.Errorf("wrong value for Weights: %v", value.Weights)
+	}
+
+	if value.Retry == nil || *value.Retry != 3 {
+		t.Errorf("wrong value for Retry: %v", value.Retry)
+	}
+
+	if !value.Enabled {
+		t.Error("wrong value for Enabled")
+	}
+
+	if len(value.Hosts) != 2 || !value.Hosts[1].Equal(net.ParseIP("10.0.0.2")) {
+		t.Errorf("wrong value for Hosts: %v", value.Hosts)
+	}
+
+	if value.Both != "plain" {
+		t.Errorf("wrong value for Both: %q", value.Both)
+	}
+
+	if !maps.Equal(value.Lookup, map[int]time.Month{1: time.January, 12: time.December}) {
+		t.Errorf("wrong value for Lookup: %v", value.Lookup)
+	}
+
+	var invalid struct {
+		Routes []jsonRoute `default_json:"{\"path\":\"/\"}"`
+	}
+
+	if err := defaults.Set(&invalid); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+
+	var trailing struct {
+		Labels map[string]string `default_json:"{\"env\":\"dev\"} garbage"`
+	}
+
+	if err := defaults.Set(&trailing); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for trailing data, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package defaults
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// splitList splits a list default value into items, for elements of type itemsType.
+// "[]" is an empty list whatever the type of the items. The list may be enclosed in brackets,
+// unless its items are flat values that may themselves be written in brackets (see [applier.hasDelimiters]).
+// Items of composite types keep their escape sequences, so that they can be split again.
+func (a *applier) splitList(value string, itemsType reflect.Type) []string {
+	if value == "[]" {
+		return nil
+	}
+
+	if !a.hasDelimiters(itemsType) {
+		var enclosed bool
+		if value, enclosed = trimDelimiters(value, '[', ']'); enclosed && value == "" {
+			return nil
+		}
+	}
+
+	if !a.isComposite(itemsType) {
+		return asList(value)
+	}
+
+	return splitTopLevel(value, ',', -1)
+}
+
+// parseStructLiteral parses a "{Field:value,Other:value}" default value as a struct.
+// Fields that are not listed in the literal receive their own defaults.
+func (a *applier) parseStructLiteral(target reflect.Value, value string) (reflect.Value, []error) {
+	var errs []error
+
+	result := reflect.New(target.Type()).Elem()
+
+	value, _ = trimDelimiters(value, '{', '}')
+	if value != "" {
+		for _, item := range splitTopLevel(value, ',', -1) {
+			nameValue := splitTopLevel(item, ':', 2)
+			if len(nameValue) != 2 {
+				errs = append(errs, fmt.Errorf("%w: expected \"<field>:<value>\", got %q", ErrInvalidFormat, item))
+
+				continue
+			}
+
+			name := strings.TrimSpace(nameValue[0])
+
+			typeField, ok := result.Type().FieldByNameFunc(func(fieldName string) bool {
+				return strings.EqualFold(fieldName, name)
+			})
+			if !ok || !typeField.IsExported() || len(typeField.Index) != 1 {
+				errs = append(errs, fmt.Errorf("%w: unknown field %q", ErrInvalidFormat, name))
+
+				continue
+			}
+
+			field := result.Field(typeField.Index[0])
+
+			fieldValue, err := a.parseLiteralItem(field, a.literalItem(field.Type(), nameValue[1]))
+			if len(err) > 0 {
+				errs = append(errs, addErrorsPrefixes(typeField.Name, err)...)
+
+				continue
+			}
+
+			field.Set(fieldValue)
+		}
+	}
+
+	if len(errs) > 0 {
+		return reflect.Value{}, errs
+	}
+
+	return a.parseStruct(result, false)
+}
+
+// literalItem prepares an item extracted from a composite literal to be parsed as a value of type typ.
+func (a *applier) literalItem(typ reflect.Type, item string) string {
+	if a.isComposite(typ) {
+		return item
+	}
+
+	return unescape(item)
+}
+
+// parseLiteralItem parses an item extracted from a composite literal.
+func (a *applier) parseLiteralItem(target reflect.Value, item string) (reflect.Value, []error) {
+	inLiteral := a.inLiteral
+	a.inLiteral = true
+
+	defer func() { a.inLiteral = inLiteral }()
+
+	return a.parse(target, item, true, true)
+}
+
+// hasDelimiters reports whether the outer brackets or braces of a list or map of values of type typ
+// are part of its items, as in the flat syntax, rather than delimiters of a composite literal.
+//
+// This is the case at the top level of a tag when the items are strings, or values of a type
+// with its own parser, whose text may start with a bracket or a brace: `default:"[debug]"`
+// is a list with the single item "[debug]". In nested literals, brackets and braces are always delimiters.
+func (a *applier) hasDelimiters(typ reflect.Type) bool {
+	if a.inLiteral || a.isComposite(typ) {
+		return false
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if a.hasParser(typ) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.Chan:
+		return false
+	default:
+		return true
+	}
+}
+
+// isComposite reports whether values of type typ are parsed from composite literals.
+func (a *applier) isComposite(typ reflect.Type) bool {
+	if a.hasParser(typ) {
+		return false
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.Struct:
+		return true
+	case reflect.Pointer:
+		return a.isComposite(typ.Elem())
+	default:
+		return false
+	}
+}
+
+// splitTopLevel splits src around the sep characters that are neither escaped
+// nor nested in brackets or braces, into at most n items (or all items if n is negative).
+// Escape sequences are kept in the items.
+func splitTopLevel(src string, sep rune, n int) []string {
+	var (
+		result  []string
+		depth   int
+		start   int
+		escaped bool
+	)
+
+	for i, char := range src {
+		if n >= 0 && len(result) == n-1 {
+			break
+		}
+
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '[' || char == '{':
+			depth++
+		case char == ']' || char == '}':
+			depth--
+		case char == sep && depth == 0:
+			result = append(result, src[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(result, src[start:])
+}
+
+// trimDelimiters removes the opening and closing delimiters around value,
+// if they enclose the whole value.
+func trimDelimiters(value string, opening, closing byte) (string, bool) {
+	if len(value) < 2 || value[0] != opening || value[len(value)-1] != closing {
+		return value, false
+	}
+
+	var (
+		depth   int
+		escaped bool
+	)
+
+	for i, char := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '[' || char == '{':
+			depth++
+		case char == ']' || char == '}':
+			depth--
+
+			if depth == 0 {
+				if i == len(value)-1 {
+					return value[1:i], true
+				}
+
+				return value, false
+			}
+		}
+	}
+
+	return value, false
+}
+
+// unescape removes the backslashes that escape characters in src.
+func unescape(src string) string {
+	if !strings.Contains(src, "\\") {
+		return src
+	}
+
+	var (
+		result  strings.Builder
+		escaped bool
+	)
+
+	for _, char := range src {
+		if char == '\\' && !escaped {
+			escaped = true
+
+			continue
+		}
+
+		escaped = false
+
+		result.WriteRune(char)
+	}
+
+	return result.String()
+}
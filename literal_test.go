@@ -0,0 +1,124 @@
+package defaults_test
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type point struct {
+	X int `default:"-1"`
+	Y int `default:"-1"`
+}
+
+type limits struct {
+	Rate    int           `default:"10"`
+	Burst   int           `default:"20"`
+	Timeout time.Duration `default:"1s"`
+}
+
+func TestCompositeLiterals(t *testing.T) {
+	t.Parallel()
+
+	var value struct {
+		Matrix  [][]string          `default:"[[a,b],[c\\,d],[]]"`
+		Ports   map[string][]int    `default:"{web:[80,443],ssh:[22]}"`
+		Points  []point             `default:"[{X:1,Y:2},{x:3}]"`
+		Limits  map[string]limits   `default:"{api:{Rate:100,Timeout:5s},web:{}}"`
+		Flat    []int               `default:"[1,2]"`
+		Legacy  map[string]int      `default:"one:1,two:2"`
+		Empty   []int               `default:"[]"`
+		Origin  point               `default:"{Y:7}"`
+		Grid    [2][2]int           `default:"[[1,2],[3,4]]"`
+		Tags    map[string][]string `default:"env:[prod,eu]"`
+		Escaped [][]string          `default:"[[\\[x\\],y]]"`
+	}
+
+	if err := defaults.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if !slices.EqualFunc(value.Matrix, [][]string{{"a", "b"}, {"c,d"}, {}}, slices.Equal) {
+		t.Errorf("wrong value for Matrix: %q", value.Matrix)
+	}
+
+	if !maps.EqualFunc(value.Ports, map[string][]int{"web": {80, 443}, "ssh": {22}}, slices.Equal) {
+		t.Errorf("wrong value for Ports: %v", value.Ports)
+	}
+
+	if !slices.Equal(value.Points, []point{{1, 2}, {3, -1}}) {
+		t.Errorf("wrong value for Points: %v", value.Points)
+	}
+
+	if !maps.Equal(value.Limits, map[string]limits{"api": {100, 20, 5 * time.Second}, "web": {10, 20, time.Second}}) {
+		t.Errorf("wrong value for Limits: %v", value.Limits)
+	}
+
+	if !slices.Equal(value.Flat, []int{1, 2}) {
+		t.Errorf("wrong value for Flat: %v", value.Flat)
+	}
+
+	if !maps.Equal(value.Legacy, map[string]int{"one": 1, "two": 2}) {
+		t.Errorf("wrong value for Legacy: %v", value.Legacy)
+	}
+
+	if value.Empty == nil || len(value.Empty) != 0 {
+		t.Errorf("wrong value for Empty: %v", value.Empty)
+	}
+
+	if value.Origin != (point{-1, 7}) {
+		t.Errorf("wrong value for Origin: %v", value.Origin)
+	}
+
+	if value.Grid != [2][2]int{{1, 2}, {3, 4}} {
+		t.Errorf("wrong value for Grid: %v", value.Grid)
+	}
+
+	if !maps.EqualFunc(value.Tags, map[string][]string{"env": {"prod", "eu"}}, slices.Equal) {
+		t.Errorf("wrong value for Tags: %v", value.Tags)
+	}
+
+	if !slices.EqualFunc(value.Escaped, [][]string{{"[x]", "y"}}, slices.Equal) {
+		t.Errorf("wrong value for Escaped: %q", value.Escaped)
+	}
+
+	var invalid struct {
+		Points []point `default:"[{Z:1}]"`
+	}
+
+	if err := defaults.Set(&invalid); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestCompositeLiteralsFlatStrings(t *testing.T) {
+	t.Parallel()
+
+	// At the top level of a tag, brackets and braces around strings are part of the values,
+	// as they were before the bracketed grammar.
+	var value struct {
+		Levels []string          `default:"[debug]"`
+		Labels map[string]string `default:"{a:b}"`
+		Nested [][]string        `default:"[[debug]]"`
+	}
+
+	if err := defaults.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if !slices.Equal(value.Levels, []string{"[debug]"}) {
+		t.Errorf("wrong value for Levels: %q", value.Levels)
+	}
+
+	if !maps.Equal(value.Labels, map[string]string{"{a": "b}"}) {
+		t.Errorf("wrong value for Labels: %q", value.Labels)
+	}
+
+	if !slices.EqualFunc(value.Nested, [][]string{{"debug"}}, slices.Equal) {
+		t.Errorf("wrong value for Nested: %q", value.Nested)
+	}
+}
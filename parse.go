@@ -6,59 +6,171 @@ import (
 	"strconv"
 )
 
-func parseStruct(target reflect.Value, overwrite bool) (reflect.Value, []error) {
+func (a *applier) parseStruct(target reflect.Value, overwrite bool) (reflect.Value, []error) {
 	var errs []error
 
-	for i := range target.NumField() {
-		typeField := target.Type().Field(i)
-		if !typeField.IsExported() {
-			continue
-		}
+	if !target.CanAddr() {
+		// Hooks need a pointer receiver and fields need to be settable.
+		addressable := reflect.New(target.Type()).Elem()
+		addressable.Set(target)
+		target = addressable
+	}
+
+	if a.validating {
+		return target, a.validateStruct(target)
+	}
+
+	if a.checking {
+		// The tags of nested structs are checked by the static walk.
+		return target, nil
+	}
+
+	if a.callGenerated(target.Addr().Interface(), overwrite) {
+		return target, nil
+	}
+
+	if err := callBeforeHook(target); err != nil {
+		errs = append(errs, err)
+	}
+
+	plan := a.plan(target.Type())
+	if plan.err != nil {
+		return target, append(errs, plan.err)
+	}
+
+	a.scopes = append(a.scopes, target)
+	defer func() { a.scopes = a.scopes[:len(a.scopes)-1] }()
+
+	// The default values of the fields are read from their tags, not from an enclosing literal.
+	inLiteral := a.inLiteral
+	a.inLiteral = false
 
-		field := target.Field(i)
-		defaultValue, hasDefault := typeField.Tag.Lookup("default")
+	defer func() { a.inLiteral = inLiteral }()
 
-		value, err := parse(field, defaultValue, hasDefault, overwrite)
-		if len(err) > 0 {
-			errs = append(errs, addErrorsPrefixes(typeField.Name, err)...)
+	for i := range plan.fields {
+		field := &plan.fields[i]
 
-			continue
+		if err := a.parsePlannedField(target.Field(field.index), field, overwrite); len(err) > 0 {
+			errs = append(errs, addErrorsPrefixes(field.typeField.Name, err)...)
 		}
+	}
+
+	if err := callAfterHook(target); err != nil {
+		errs = append(errs, err)
+	}
 
-		if value.IsValid() && (field.IsZero() || overwrite) {
-			field.Set(value)
+	// Required fields are checked once the hooks had a chance to set them.
+	for _, field := range plan.fields {
+		if err := checkRequired(target.Field(field.index), field.typeField); err != nil {
+			errs = append(errs, addErrorsPrefixes(field.typeField.Name, []error{err})...)
 		}
 	}
 
 	return target, errs
 }
 
-func parse(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
-	// First, check if we have a specific parser for this type.
-	if result, hasParser, errs := parseSpecific(target, value, hasDefault); hasParser {
+func (a *applier) parseField(field reflect.Value, typeField reflect.StructField, overwrite bool) []error {
+	defaultValue, hasDefault, err := a.defaultValue(typeField)
+	if err != nil {
+		return []error{err}
+	}
+
+	var (
+		value reflect.Value
+		errs  []error
+	)
+
+	if jsonValue, hasJSON := typeField.Tag.Lookup(jsonTagName); !hasDefault && hasJSON {
+		hasDefault = true
+		value, errs = a.parseJSON(field, jsonValue)
+	} else if !hasDefault && field.Kind() == reflect.Pointer {
+		mode, err := a.fieldPointerMode(typeField)
+		if err != nil {
+			return []error{err}
+		}
+
+		value, errs = a.parseNestedPointer(field, mode, overwrite)
+	} else {
+		value, errs = a.parse(field, defaultValue, hasDefault, overwrite)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if value.IsValid() && (field.IsZero() || overwrite) {
+		field.Set(value)
+
+		return nil
+	}
+
+	if value.IsValid() && !hasDefault {
+		// Nested structs without a default value have been parsed in place.
+		return nil
+	}
+
+	// The default value has not been applied, apply defaults to the existing elements instead.
+	return a.parseElements(field, overwrite)
+}
+
+// defaultValue returns the default value of typeField, with providers and references resolved,
+// and reports whether the field has a default value.
+func (a *applier) defaultValue(typeField reflect.StructField) (string, bool, error) {
+	value, hasDefault := a.lookupDefault(typeField.Tag)
+
+	conditional, matched, err := a.conditionalDefault(typeField)
+	if err != nil {
+		return "", false, err
+	}
+
+	if matched {
+		value, hasDefault = conditional, true
+	}
+
+	if !hasDefault {
+		return "", false, nil
+	}
+
+	provided, isProvided, err := a.provide(typeField, value)
+	if err != nil || isProvided {
+		// Provided values are used as-is.
+		return provided, true, err
+	}
+
+	value, err = a.interpolate(provided)
+
+	return value, true, err
+}
+
+func (a *applier) parse(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
+	// First, check if a custom parser has been registered for this type.
+	if result, hasParser, errs := a.parseCustom(target, value, hasDefault); hasParser {
+		return result, errs
+	}
+
+	// Then, check if we have a specific parser for this type.
+	if result, hasParser, errs := a.parseSpecific(target, value, hasDefault); hasParser {
 		return result, errs
 	}
 
 	// Then check if it is an [encoding.TextUnmarshaler].
-	if target.CanAddr() && target.Addr().Type().Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
-		result := reflect.New(target.Type()).Elem()
-		resp := result.Addr().MethodByName("UnmarshalText").Call([]reflect.Value{reflect.ValueOf([]byte(value))})
-
-		if errIface := resp[0].Interface(); errIface != nil {
-			if err, ok := errIface.(error); ok {
-				return reflect.Value{}, []error{err}
-			}
+	if reflect.PointerTo(target.Type()).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		result := reflect.New(target.Type())
+
+		unmarshaler, _ := result.Interface().(encoding.TextUnmarshaler)
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return reflect.Value{}, []error{err}
 		}
 
-		return result, nil
+		return result.Elem(), nil
 	}
 
 	// Then, parse according to the kind of the target.
-	return parseKind(target, value, hasDefault, overwrite)
+	return a.parseKind(target, value, hasDefault, overwrite)
 }
 
 //nolint:funlen // We cannot make this shorter :-)
-func parseKind(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
+func (a *applier) parseKind(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
 	switch target.Kind() {
 	case reflect.Bool:
 		return parseWithError(target, strconv.ParseBool, value, hasDefault)
@@ -106,32 +218,36 @@ func parseKind(target reflect.Value, value string, hasDefault, overwrite bool) (
 		return parseWithErrorI(target, strconv.ParseComplex, value, 128, hasDefault)
 
 	case reflect.Array:
-		return parseArray(target, value, hasDefault)
+		return a.parseArray(target, value, hasDefault)
 
 	case reflect.Chan:
 		return makeChan(target, value, hasDefault)
 
 	case reflect.Map:
-		return parseMap(target, value, hasDefault)
+		return a.parseMap(target, value, hasDefault)
 
 	case reflect.Pointer:
-		return parsePointer(target, value, hasDefault, overwrite)
+		return a.parsePointer(target, value, hasDefault, overwrite)
 
 	case reflect.Slice:
-		return parseSlice(target, value, hasDefault)
+		return a.parseSlice(target, value, hasDefault)
 
 	case reflect.String:
-		return reflect.ValueOf(value), nil
+		return reflect.ValueOf(value).Convert(target.Type()), nil
 
 	case reflect.Struct:
-		return parseStruct(target, overwrite)
+		if hasDefault {
+			return a.parseStructLiteral(target, value)
+		}
+
+		return a.parseStruct(target, overwrite)
 
 	default:
 		return reflect.Value{}, []error{ErrUnsupportedType}
 	}
 }
 
-func parsePointer(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
+func (a *applier) parsePointer(target reflect.Value, value string, hasDefault, overwrite bool) (reflect.Value, []error) {
 	if !hasDefault {
 		return reflect.Value{}, nil
 	}
@@ -144,7 +260,7 @@ func parsePointer(target reflect.Value, value string, hasDefault, overwrite bool
 		return reflect.Value{}, nil
 	}
 
-	result, errs := parse(target.Elem(), value, hasDefault, overwrite)
+	result, errs := a.parse(target.Elem(), value, hasDefault, overwrite)
 	if len(errs) > 0 {
 		return reflect.Value{}, errs
 	}
@@ -0,0 +1,241 @@
+package defaults
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+const defaultTagName = tags.Default
+
+// Parser applies defaults according to its own configuration.
+//
+// Each Parser holds its own custom parsers and options,
+// so that libraries may configure defaults without stepping on each other.
+// The package-level functions use a default Parser.
+//
+// A Parser is safe for concurrent use.
+type Parser struct {
+	mu          sync.RWMutex
+	parsers     map[reflect.Type]customParser
+	providers   map[string]providerFunc
+	tagNames    []string
+	pointerMode PointerMode
+	profiles    []string
+	lookupEnv   func(name string) (string, bool)
+	clock       func() time.Time
+
+	// plans caches the compiled form of struct types, by type and configuration (see [planKey]).
+	// It is shared with the copies of the parser made for call options,
+	// unless these options configure other parsers.
+	plans *sync.Map
+
+	// planConfig identifies the options that the compiled plans depend on.
+	planConfig string
+
+	// prototypes caches the fully defaulted values of struct types, for Reset.
+	prototypes sync.Map
+
+	// noPlanCache disables the cache of compiled plans and prototypes, for benchmarks.
+	noPlanCache bool
+}
+
+// Option configures a [Parser].
+type Option func(*Parser)
+
+// defaultParser is the parser used by the package-level functions.
+//
+//nolint:gochecknoglobals // The package-level functions and RegisterParser share this parser.
+var defaultParser = NewParser()
+
+// NewParser returns a new [Parser] configured with the provided options.
+func NewParser(opts ...Option) *Parser {
+	parser := &Parser{
+		parsers:   map[reflect.Type]customParser{},
+		providers: builtinProviders(),
+		tagNames:  []string{defaultTagName},
+		lookupEnv: os.LookupEnv,
+		clock:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(parser)
+	}
+
+	parser.plans = &sync.Map{}
+	parser.planConfig = parser.computePlanConfig()
+
+	return parser
+}
+
+// computePlanConfig returns the identifier of the options that compiled plans depend on,
+// besides the custom parsers: the tag names, the profiles and the pointer mode.
+func (p *Parser) computePlanConfig() string {
+	return strings.Join(p.tagNames, ",") + ";" + strings.Join(p.profiles, ",") + ";" + strconv.Itoa(int(p.pointerMode))
+}
+
+// WithTagName configures the parser to read default values from the named struct tag,
+// instead of the "default" tag.
+func WithTagName(name string) Option {
+	return WithTagNames(name)
+}
+
+// WithTagNames configures the parser to read default values from the first
+// of the named struct tags that is present on each field, in order.
+//
+// For example, WithTagNames("cfgdefault", "default") reads the "cfgdefault" tag
+// and falls back to the "default" tag when a field has no "cfgdefault" tag.
+func WithTagNames(names ...string) Option {
+	return func(p *Parser) {
+		p.tagNames = slices.Clone(names)
+	}
+}
+
+// WithProfiles configures the active profiles, in order of precedence.
+//
+// For each field, the parser reads the default value from the "<tag>.<profile>" tag
+// of the first active profile that has one (for instance "default.prod"),
+// and falls back to the base tag.
+func WithProfiles(profiles ...string) Option {
+	return func(p *Parser) {
+		p.profiles = slices.Clone(profiles)
+	}
+}
+
+// Set unmarshals the tagged defaults and applies them, overwriting existing values.
+// The options, if any, apply to this call only.
+func (p *Parser) Set(target any, opts ...Option) error { return p.with(opts).apply(target, true) }
+
+// Complete unmarshals the tagged defaults and applies them to unset values, leaving non-zero values untouched.
+// The options, if any, apply to this call only.
+func (p *Parser) Complete(target any, opts ...Option) error { return p.with(opts).apply(target, false) }
+
+// with returns a copy of the parser with additional options, or the parser itself if there is no option.
+func (p *Parser) with(opts []Option) *Parser {
+	if len(opts) == 0 {
+		return p
+	}
+
+	p.mu.RLock()
+	parser := &Parser{
+		parsers:     maps.Clone(p.parsers),
+		providers:   maps.Clone(p.providers),
+		tagNames:    p.tagNames,
+		pointerMode: p.pointerMode,
+		profiles:    p.profiles,
+		lookupEnv:   p.lookupEnv,
+		clock:       p.clock,
+		plans:       p.plans,
+		noPlanCache: p.noPlanCache,
+	}
+	p.mu.RUnlock()
+
+	for _, opt := range opts {
+		opt(parser)
+	}
+
+	// WithParser discards the shared plans, which have been compiled with other parsers.
+	if parser.plans == nil {
+		parser.plans = &sync.Map{}
+	}
+
+	parser.planConfig = parser.computePlanConfig()
+
+	return parser
+}
+
+// Validate checks the values of target against their validation tags,
+// without applying any default. See [Validate] for details.
+func (p *Parser) Validate(target any) error {
+	return p.run(target, &applier{Parser: p, validating: true}, false)
+}
+
+// CompleteAndValidate applies the defaults to unset values like [Parser.Complete],
+// then checks the values against their validation tags like [Parser.Validate].
+// The options, if any, apply to this call only.
+func (p *Parser) CompleteAndValidate(target any, opts ...Option) error {
+	parser := p.with(opts)
+
+	if err := parser.apply(target, false); err != nil {
+		return err
+	}
+
+	return parser.Validate(target)
+}
+
+func (p *Parser) apply(target any, overwrite bool) error {
+	return p.run(target, &applier{Parser: p}, overwrite)
+}
+
+func (p *Parser) run(target any, app *applier, overwrite bool) error {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Pointer {
+		return ErrMustBePointerToAStruct
+	}
+
+	elem := val.Elem()
+	if elem.Kind() != reflect.Struct {
+		return ErrMustBePointerToAStruct
+	}
+
+	if app.callGenerated(target, overwrite) {
+		return nil
+	}
+
+	_, errs := app.parseStruct(elem, overwrite)
+
+	return errors.Join(errs...)
+}
+
+// applier holds the state of a single application of defaults.
+type applier struct {
+	*Parser
+
+	// allocating holds the struct types being allocated, to stop recursion on recursive types.
+	allocating map[reflect.Type]bool
+
+	// scopes holds the structs being parsed, from the outermost to the innermost,
+	// in which references to other fields are resolved.
+	scopes []reflect.Value
+
+	// currentTime is the time against which relative times are resolved.
+	currentTime time.Time
+
+	// validating is true when checking values against their validation tags instead of applying defaults.
+	validating bool
+
+	// checking is true when checking the tags of a type, in which case nested structs are not parsed.
+	checking bool
+
+	// inLiteral is true when parsing the items of a composite literal,
+	// in which brackets and braces always delimit nested values.
+	inLiteral bool
+}
+
+// lookupDefault returns the default value from the first configured tag present in tag,
+// looking up the tags of the active profiles first.
+func (p *Parser) lookupDefault(tag reflect.StructTag) (string, bool) {
+	for _, profile := range p.profiles {
+		for _, name := range p.tagNames {
+			if value, ok := tag.Lookup(name + "." + profile); ok {
+				return value, true
+			}
+		}
+	}
+
+	for _, name := range p.tagNames {
+		if value, ok := tag.Lookup(name); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,80 @@
+package defaults_test
+
+import (
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type regionCode string
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	parser := defaults.NewParser(
+		defaults.WithTagName("cfgdefault"),
+		defaults.WithParser(func(value string) (regionCode, error) {
+			return regionCode("region-" + value), nil
+		}),
+	)
+
+	type config struct {
+		Region regionCode `cfgdefault:"eu"     default:"us"`
+		Name   string     `cfgdefault:"scoped" default:"global"`
+	}
+
+	var scoped config
+	if err := parser.Set(&scoped); err != nil {
+		t.Fatalf("failed to apply scoped defaults: %s", err)
+	}
+
+	if scoped.Region != "region-eu" || scoped.Name != "scoped" {
+		t.Errorf("wrong scoped value: %+v", scoped)
+	}
+
+	var global config
+	if err := defaults.Set(&global); err != nil {
+		t.Fatalf("failed to apply global defaults: %s", err)
+	}
+
+	if global.Region != "us" || global.Name != "global" {
+		t.Errorf("wrong global value: %+v", global)
+	}
+
+	scoped.Name = "custom"
+	if err := parser.Complete(&scoped); err != nil {
+		t.Fatalf("failed to complete scoped defaults: %s", err)
+	}
+
+	if scoped.Name != "custom" {
+		t.Errorf("Complete overwrote Name: %q", scoped.Name)
+	}
+}
+
+func TestParserTagNames(t *testing.T) {
+	t.Parallel()
+
+	parser := defaults.NewParser(defaults.WithTagNames("cfgdefault", "default"))
+
+	var value struct {
+		Both    string `cfgdefault:"specific" default:"generic"`
+		Generic string `default:"generic"`
+		Other   string `defaults:"ignored"`
+	}
+
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Both != "specific" {
+		t.Errorf("wrong value for Both: %q", value.Both)
+	}
+
+	if value.Generic != "generic" {
+		t.Errorf("wrong value for Generic: %q", value.Generic)
+	}
+
+	if value.Other != "" {
+		t.Errorf("wrong value for Other: %q", value.Other)
+	}
+}
@@ -0,0 +1,185 @@
+package defaults
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// structPlan is the compiled form of a struct type:
+// the order in which its fields are parsed, and the default values that can be parsed in advance.
+type structPlan struct {
+	fields []fieldPlan
+	err    error
+}
+
+// fieldPlan is the compiled form of a struct field.
+type fieldPlan struct {
+	index     int
+	typeField reflect.StructField
+
+	// static is true when the default value does not depend on the context it is applied in,
+	// in which case it has been parsed in advance into value, or into errs if it is invalid.
+	static bool
+	value  reflect.Value
+	errs   []error
+}
+
+// planKey identifies a compiled plan: the same type is compiled differently
+// depending on the tag names, profiles and pointer mode.
+type planKey struct {
+	typ    reflect.Type
+	config string
+}
+
+// plan returns the compiled form of typ, which is compiled on first use and cached by the parser.
+func (a *applier) plan(typ reflect.Type) *structPlan {
+	key := planKey{typ: typ, config: a.planConfig}
+
+	if cached, ok := a.plans.Load(key); ok {
+		plan, _ := cached.(*structPlan)
+
+		return plan
+	}
+
+	plan := a.compile(typ)
+
+	if !a.noPlanCache {
+		a.plans.Store(key, plan)
+	}
+
+	return plan
+}
+
+func (a *applier) compile(typ reflect.Type) *structPlan {
+	order, err := a.computeFieldOrder(typ)
+	if err != nil {
+		return &structPlan{err: err}
+	}
+
+	plan := &structPlan{fields: make([]fieldPlan, 0, len(order))}
+
+	for _, i := range order {
+		field := fieldPlan{index: i, typeField: typ.Field(i)}
+		field.value, field.errs, field.static = a.parseStatic(field.typeField)
+		plan.fields = append(plan.fields, field)
+	}
+
+	return plan
+}
+
+// parseStatic parses the default value of typeField in advance, and reports whether it is static,
+// that is if it does not depend on conditions, providers, references, environment variables,
+// the current time or hooks, and may be cloned.
+func (p *Parser) parseStatic(typeField reflect.StructField) (reflect.Value, []error, bool) {
+	if _, ok := typeField.Tag.Lookup(conditionalTagName); ok {
+		return reflect.Value{}, nil, false
+	}
+
+	value, ok := p.lookupDefault(typeField.Tag)
+	if !ok || tags.IsDynamic(value) || !isCloneable(typeField.Type, nil) {
+		return reflect.Value{}, nil, false
+	}
+
+	app := &applier{Parser: p}
+
+	value, _, err := app.defaultValue(typeField)
+	if err != nil {
+		return reflect.Value{}, []error{err}, true
+	}
+
+	result, errs := app.parse(reflect.New(typeField.Type).Elem(), value, true, true)
+
+	if !app.currentTime.IsZero() {
+		// Relative times are resolved when applying defaults.
+		return reflect.Value{}, nil, false
+	}
+
+	return result, errs, true
+}
+
+// parsePlannedField applies the default value of a field, parsed in advance if it is static.
+func (a *applier) parsePlannedField(field reflect.Value, plan *fieldPlan, overwrite bool) []error {
+	if !plan.static {
+		return a.parseField(field, plan.typeField, overwrite)
+	}
+
+	if len(plan.errs) > 0 {
+		return plan.errs
+	}
+
+	if plan.value.IsValid() && (field.IsZero() || overwrite) {
+		field.Set(cloneValue(plan.value))
+
+		return nil
+	}
+
+	// The default value has not been applied, apply defaults to the existing elements instead.
+	return a.parseElements(field, overwrite)
+}
+
+// Plan applies the defaults of type T, compiled once by [Compile].
+// Its benefit is that invalid default values are reported once, when compiling,
+// rather than on each call: applying a Plan costs the same as [Parser.Set] or [Parser.Complete],
+// which use the same compiled plans.
+//
+// A Plan is safe for concurrent use.
+type Plan[T any] struct {
+	parser *Parser
+}
+
+// Compile compiles the defaults of T, which must be a struct type, with the provided options,
+// and returns a [Plan] applying them.
+//
+// Every struct type is compiled once by each [Parser] and each set of tag names, profiles and pointer mode,
+// including those passed as options to [Set] or [Complete] (options configuring parsers excepted),
+// whether by Compile or on first use by [Set] or [Complete]: the order of its fields is computed,
+// and the default values that do not depend on the context are parsed in advance,
+// then deep-copied into each target. Defaults using conditions, providers, references,
+// environment variables or relative times, as well as struct literals, are parsed on each call.
+//
+// The errors of the default values parsed in advance are returned.
+func Compile[T any](opts ...Option) (*Plan[T], error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return nil, ErrMustBePointerToAStruct
+	}
+
+	parser := defaultParser.with(opts)
+
+	plan := (&applier{Parser: parser}).plan(typ)
+	if plan.err != nil {
+		return nil, plan.err
+	}
+
+	var errs []error
+
+	for _, field := range plan.fields {
+		if len(field.errs) > 0 {
+			errs = append(errs, addErrorsPrefixes(field.typeField.Name, field.errs)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &Plan[T]{parser: parser}, nil
+}
+
+// Set applies the defaults to target, overwriting existing values, like [Set].
+func (p *Plan[T]) Set(target *T) error { return p.apply(target, true) }
+
+// Complete applies the defaults to the unset values of target, like [Complete].
+func (p *Plan[T]) Complete(target *T) error { return p.apply(target, false) }
+
+func (p *Plan[T]) apply(target *T, overwrite bool) error {
+	if target == nil {
+		return ErrMustBePointerToAStruct
+	}
+
+	_, errs := (&applier{Parser: p.parser}).parseStruct(reflect.ValueOf(target).Elem(), overwrite)
+
+	return errors.Join(errs...)
+}
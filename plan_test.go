@@ -0,0 +1,219 @@
+package defaults_test
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type plannedConfig struct {
+	Name     string            `default:"app"`
+	Port     int               `default:"8080"`
+	Timeout  time.Duration     `default:"5s"`
+	Hosts    []string          `default:"a,b,c"`
+	Limits   map[string]int    `default:"read:10,write:5"`
+	Retries  *int              `default:"3"`
+	Total    big.Int           `default:"12345678901234567890"`
+	Events   chan string       `default:"4"`
+	URL      string            `default:"http://${Name}:${Port}"`
+	Deadline time.Time         `default:"now+1h"`
+	Backends []plannedBackend  `default:"[{Host:one}]"`
+	Labels   map[string]string `default_json:"{\"env\":\"dev\"}"`
+}
+
+type plannedBackend struct {
+	Host string `default:"localhost"`
+	Port int    `default:"80"`
+}
+
+type invalidPlannedConfig struct {
+	Count int `default:"abc"`
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	stamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	plan, err := defaults.Compile[plannedConfig](defaults.WithClock(func() time.Time { return stamp }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second plannedConfig
+	if err := plan.Set(&first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plan.Set(&second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.URL != "http://app:8080" || !first.Deadline.Equal(stamp.Add(time.Hour)) ||
+		first.Backends[0].Port != 80 || first.Labels["env"] != "dev" || cap(first.Events) != 4 {
+		t.Errorf("unexpected values: %+v", first)
+	}
+
+	// Values parsed in advance are not shared between targets.
+	first.Hosts[0] = "changed"
+	first.Limits["read"] = 0
+	*first.Retries = 0
+	first.Total.SetInt64(0)
+
+	if second.Hosts[0] != "a" || second.Limits["read"] != 10 || *second.Retries != 3 ||
+		second.Total.String() != "12345678901234567890" || first.Events == second.Events {
+		t.Errorf("values are shared between targets: %+v", second)
+	}
+
+	completed := plannedConfig{Name: "custom"}
+	if err := plan.Complete(&completed); err != nil {
+		t.Fatal(err)
+	}
+
+	if completed.Name != "custom" || completed.URL != "http://custom:8080" || completed.Port != 8080 {
+		t.Errorf("unexpected values: %+v", completed)
+	}
+
+	if _, err := defaults.Compile[invalidPlannedConfig](); err == nil {
+		t.Error("expected an error for an invalid default")
+	}
+
+	if _, err := defaults.Compile[int](); !errors.Is(err, defaults.ErrMustBePointerToAStruct) {
+		t.Errorf("expected ErrMustBePointerToAStruct, got %v", err)
+	}
+}
+
+func TestPlanErrorsRepeated(t *testing.T) {
+	t.Parallel()
+
+	const expected = `Count: strconv.Atoi: parsing "abc": invalid syntax`
+
+	parser := defaults.NewParser()
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 3 {
+				var config invalidPlannedConfig
+				if err := parser.Set(&config); err == nil || err.Error() != expected {
+					t.Errorf("expected %q, got %v", expected, err)
+				}
+
+				if _, err := defaults.Compile[invalidPlannedConfig](); err == nil || err.Error() != expected {
+					t.Errorf("expected %q, got %v", expected, err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+type profiledPlanConfig struct {
+	Region  string        `default:"local" default.prod:"eu-west"`
+	Timeout time.Duration `default:"5s"`
+}
+
+func TestPlanOptions(t *testing.T) {
+	t.Parallel()
+
+	parser := defaults.NewParser()
+
+	// Calls with and without options share the cache of the parser, each with their own plans.
+	for range 2 {
+		var local, prod, custom profiledPlanConfig
+		if err := parser.Set(&local); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := parser.Set(&prod, defaults.WithProfiles("prod")); err != nil {
+			t.Fatal(err)
+		}
+
+		err := parser.Set(&custom, defaults.WithParser(func(string) (time.Duration, error) { return time.Minute, nil }))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if local.Region != "local" || prod.Region != "eu-west" || local.Timeout != 5*time.Second {
+			t.Errorf("unexpected values: %+v, %+v", local, prod)
+		}
+
+		if custom.Timeout != time.Minute {
+			t.Errorf("expected the parser passed as option to be used, got %v", custom.Timeout)
+		}
+	}
+}
+
+type benchmarkConfig struct {
+	Name     string            `default:"app"`
+	Host     string            `default:"localhost"`
+	Port     int               `default:"8080"`
+	Debug    bool              `default:"true"`
+	Ratio    float64           `default:"0.75"`
+	Timeout  time.Duration     `default:"5s"`
+	Interval time.Duration     `default:"1m30s"`
+	Hosts    []string          `default:"a,b,c,d"`
+	Ports    []int             `default:"80,443,8080"`
+	Limits   map[string]int    `default:"read:10,write:5,delete:1"`
+	Labels   map[string]string `default:"env:dev,team:core"`
+	Backend  plannedBackend
+}
+
+func BenchmarkSet(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) {
+		parser := defaults.NewParser(defaults.WithoutPlanCache())
+
+		for range b.N {
+			var config benchmarkConfig
+			if err := parser.Set(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		parser := defaults.NewParser()
+
+		for range b.N {
+			var config benchmarkConfig
+			if err := parser.Set(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("options", func(b *testing.B) {
+		parser := defaults.NewParser()
+
+		for range b.N {
+			var config benchmarkConfig
+			if err := parser.Set(&config, defaults.WithProfiles("prod")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("plan", func(b *testing.B) {
+		plan, err := defaults.Compile[benchmarkConfig]()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for range b.N {
+			var config benchmarkConfig
+			if err := plan.Set(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package defaults
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// PointerMode defines how defaults are applied to pointers to structs that have no default value.
+type PointerMode int
+
+const (
+	// PointerSkip leaves pointers without a default value untouched. This is the default mode.
+	PointerSkip PointerMode = iota
+
+	// PointerExisting applies defaults to the structs pointed by non-nil pointers,
+	// leaving nil pointers untouched.
+	PointerExisting
+
+	// PointerAllocate allocates nil pointers to structs and applies defaults to the pointed structs,
+	// as well as to the structs pointed by non-nil pointers.
+	// Recursive types are only allocated once in each branch.
+	PointerAllocate
+)
+
+const pointerModeTagName = tags.PointerMode
+
+// WithPointerMode configures how the parser handles pointers to structs that have no default value.
+// The mode may be overridden for a specific field with the "default_ptr" tag,
+// whose value is "skip", "existing" or "allocate".
+func WithPointerMode(mode PointerMode) Option {
+	return func(p *Parser) {
+		p.pointerMode = mode
+	}
+}
+
+func (a *applier) fieldPointerMode(typeField reflect.StructField) (PointerMode, error) {
+	value, ok := typeField.Tag.Lookup(pointerModeTagName)
+	if !ok {
+		return a.pointerMode, nil
+	}
+
+	switch value {
+	case "skip":
+		return PointerSkip, nil
+	case "existing":
+		return PointerExisting, nil
+	case "allocate":
+		return PointerAllocate, nil
+	default:
+		return PointerSkip, fmt.Errorf(
+			"%w: %s expects \"skip\", \"existing\" or \"allocate\", got %q", ErrInvalidFormat, pointerModeTagName, value,
+		)
+	}
+}
+
+// parseNestedPointer applies the defaults of the struct pointed by target, according to mode.
+func (a *applier) parseNestedPointer(target reflect.Value, mode PointerMode, overwrite bool) (reflect.Value, []error) {
+	pointedType := target.Type().Elem()
+	if mode == PointerSkip || pointedType.Kind() != reflect.Struct || a.hasParser(pointedType) {
+		return reflect.Value{}, nil
+	}
+
+	if !target.IsNil() {
+		_, errs := a.parseStruct(target.Elem(), overwrite)
+
+		return reflect.Value{}, errs
+	}
+
+	if mode != PointerAllocate || a.allocating[pointedType] {
+		return reflect.Value{}, nil
+	}
+
+	if a.allocating == nil {
+		a.allocating = map[reflect.Type]bool{}
+	}
+
+	a.allocating[pointedType] = true
+	defer delete(a.allocating, pointedType)
+
+	result := reflect.New(pointedType)
+	_, errs := a.parseStruct(result.Elem(), overwrite)
+
+	return result, errs
+}
+
+// hasParser reports whether values of type typ are parsed as a whole,
+// instead of according to their kind.
+func (a *applier) hasParser(typ reflect.Type) bool {
+	zero := reflect.Zero(typ)
+
+	if _, hasCustom, _ := a.parseCustom(zero, "", false); hasCustom {
+		return true
+	}
+
+	if _, hasSpecific, _ := a.parseSpecific(zero, "", false); hasSpecific {
+		return true
+	}
+
+	return reflect.PointerTo(typ).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
@@ -0,0 +1,73 @@
+package defaults_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type tlsConfig struct {
+	Cert string `default:"/etc/ssl/cert.pem"`
+	Key  string `default:"/etc/ssl/key.pem"`
+}
+
+type node struct {
+	Name string `default:"node"`
+	Next *node
+}
+
+func TestPointerMode(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		TLS      *tlsConfig
+		Existing *tlsConfig `default_ptr:"existing"`
+		Skipped  *tlsConfig `default_ptr:"skip"`
+		Nodes    *node
+	}
+
+	var value config
+
+	parser := defaults.NewParser(defaults.WithPointerMode(defaults.PointerAllocate))
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.TLS == nil || value.TLS.Cert != "/etc/ssl/cert.pem" || value.TLS.Key != "/etc/ssl/key.pem" {
+		t.Errorf("wrong value for TLS: %+v", value.TLS)
+	}
+
+	if value.Existing != nil {
+		t.Errorf("Existing should be nil, got %+v", value.Existing)
+	}
+
+	if value.Nodes == nil || value.Nodes.Name != "node" || value.Nodes.Next != nil {
+		t.Errorf("wrong value for Nodes: %+v", value.Nodes)
+	}
+
+	value = config{Existing: &tlsConfig{Key: "custom.pem"}, Skipped: &tlsConfig{}}
+	if err := defaults.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.TLS != nil {
+		t.Errorf("TLS should be nil, got %+v", value.TLS)
+	}
+
+	if value.Existing.Cert != "/etc/ssl/cert.pem" || value.Existing.Key != "custom.pem" {
+		t.Errorf("wrong value for Existing: %+v", value.Existing)
+	}
+
+	if *value.Skipped != (tlsConfig{}) {
+		t.Errorf("Skipped should be untouched, got %+v", value.Skipped)
+	}
+
+	var invalid struct {
+		TLS *tlsConfig `default_ptr:"always"`
+	}
+
+	if err := defaults.Set(&invalid); !errors.Is(err, defaults.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
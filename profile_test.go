@@ -0,0 +1,64 @@
+package defaults_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type profiledConfig struct {
+	PoolSize int           `default:"10"    default.dev:"4"       default.prod:"100"`
+	LogLevel string        `default:"info"  default.dev:"debug"`
+	Timeout  time.Duration `default:"30s"   default.staging:"1m"`
+	Region   string        `default:"local" default.prod:"eu-west"`
+}
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		opts     []defaults.Option
+		expected profiledConfig
+	}{
+		"base": {nil, profiledConfig{10, "info", 30 * time.Second, "local"}},
+		"dev":  {[]defaults.Option{defaults.WithProfiles("dev")}, profiledConfig{4, "debug", 30 * time.Second, "local"}},
+		"prod": {[]defaults.Option{defaults.WithProfiles("prod")}, profiledConfig{100, "info", 30 * time.Second, "eu-west"}},
+		"staging then prod": {
+			[]defaults.Option{defaults.WithProfiles("staging", "prod")},
+			profiledConfig{100, "info", time.Minute, "eu-west"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var value profiledConfig
+			if err := defaults.Set(&value, test.opts...); err != nil {
+				t.Fatalf("failed to apply defaults: %s", err)
+			}
+
+			if value != test.expected {
+				t.Errorf("wrong value: %+v, expected %+v", value, test.expected)
+			}
+		})
+	}
+
+	parser := defaults.NewParser(defaults.WithProfiles("dev"))
+
+	value := defaults.MustNew[profiledConfig]()
+	if err := parser.Complete(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.PoolSize != 10 {
+		t.Errorf("Complete overwrote PoolSize: %d", value.PoolSize)
+	}
+
+	if err := parser.Set(&value, defaults.WithProfiles("prod")); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.PoolSize != 100 {
+		t.Errorf("wrong value for PoolSize with per-call profile: %d", value.PoolSize)
+	}
+}
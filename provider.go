@@ -0,0 +1,134 @@
+package defaults
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+
+	"github.com/willoma/defaults/internal/tags"
+)
+
+// providerFunc computes a default value for field, arg being the optional argument of the provider reference.
+type providerFunc func(field reflect.StructField, arg string) (string, error)
+
+const defaultRandomBytes = 16
+
+// builtinProviders returns the providers that are registered in every [Parser].
+func builtinProviders() map[string]providerFunc {
+	return map[string]providerFunc{
+		"hostname": func(reflect.StructField, string) (string, error) {
+			return os.Hostname()
+		},
+		"numcpu": func(reflect.StructField, string) (string, error) {
+			return strconv.Itoa(runtime.NumCPU()), nil
+		},
+		"gomaxprocs": func(reflect.StructField, string) (string, error) {
+			return strconv.Itoa(runtime.GOMAXPROCS(0)), nil
+		},
+		"randomhex": func(_ reflect.StructField, arg string) (string, error) {
+			return randomString(arg, hex.EncodeToString)
+		},
+		"randombase64": func(_ reflect.StructField, arg string) (string, error) {
+			return randomString(arg, base64.StdEncoding.EncodeToString)
+		},
+		"workdir": func(reflect.StructField, string) (string, error) {
+			return os.Getwd()
+		},
+		"execdir": func(reflect.StructField, string) (string, error) {
+			executable, err := os.Executable()
+			if err != nil {
+				return "", err
+			}
+
+			return filepath.Dir(executable), nil
+		},
+	}
+}
+
+// RegisterProvider registers a provider of dynamic default values in the default [Parser],
+// used by the package-level functions. Use [WithProvider] to configure another [Parser].
+//
+// A field whose default value is "@name" receives the value returned by the provider registered as name,
+// which is then parsed like any other default value. A default value starting with a literal "@"
+// must be written with "@@".
+//
+// The following providers are built in:
+//
+//   - "hostname": the host name, from [os.Hostname]
+//   - "numcpu": the number of CPUs, from [runtime.NumCPU]
+//   - "gomaxprocs": the current GOMAXPROCS value, from [runtime.GOMAXPROCS]
+//   - "randomhex:N": N random bytes (16 if omitted), hex-encoded
+//   - "randombase64:N": N random bytes (16 if omitted), base64-encoded
+//   - "workdir": the current working directory, from [os.Getwd]
+//   - "execdir": the directory of the current executable, from [os.Executable]
+//
+// Registering a provider with the name of an existing provider replaces it.
+func RegisterProvider(name string, provider func(field reflect.StructField) (string, error)) {
+	defaultParser.mu.Lock()
+	defer defaultParser.mu.Unlock()
+
+	defaultParser.providers[name] = wrapProvider(provider)
+}
+
+// WithProvider configures the parser to use the provided provider of dynamic default values.
+// See [RegisterProvider] for details.
+func WithProvider(name string, provider func(field reflect.StructField) (string, error)) Option {
+	return func(p *Parser) {
+		p.providers[name] = wrapProvider(provider)
+	}
+}
+
+func wrapProvider(provider func(field reflect.StructField) (string, error)) providerFunc {
+	return func(field reflect.StructField, _ string) (string, error) {
+		return provider(field)
+	}
+}
+
+// provide resolves a "@name" or "@name:arg" provider reference in value.
+// It reports whether value is a provider reference; "@@" escapes a literal "@".
+func (a *applier) provide(field reflect.StructField, value string) (result string, isProvided bool, err error) {
+	name, arg, ok := tags.Provider(value)
+	if !ok {
+		return tags.Unescape(value), false, nil
+	}
+
+	a.mu.RLock()
+	provider, ok := a.providers[name]
+	a.mu.RUnlock()
+
+	if !ok {
+		return "", true, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+
+	result, err = provider(field, arg)
+	if err != nil {
+		return "", true, fmt.Errorf("provider %q: %w", name, err)
+	}
+
+	return result, true, nil
+}
+
+func randomString(size string, encode func([]byte) string) (string, error) {
+	length := defaultRandomBytes
+
+	if size != "" {
+		var err error
+
+		if length, err = strconv.Atoi(size); err != nil {
+			return "", err
+		}
+	}
+
+	data := make([]byte, max(length, 0))
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return encode(data), nil
+}
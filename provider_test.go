@@ -0,0 +1,99 @@
+package defaults_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+func TestProviders(t *testing.T) {
+	t.Parallel()
+
+	parser := defaults.NewParser(defaults.WithProvider("fieldname", func(field reflect.StructField) (string, error) {
+		return "name-of-" + field.Name, nil
+	}))
+
+	var value struct {
+		Host    string `default:"@hostname"`
+		Workers int    `default:"@numcpu"`
+		Procs   uint   `default:"@gomaxprocs"`
+		Token   string `default:"@randomhex:8"`
+		Secret  string `default:"@randombase64"`
+		Dir     string `default:"@workdir"`
+		Custom  string `default:"@fieldname"`
+		Handle  string `default:"@@willow"`
+	}
+
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if hostname, _ := os.Hostname(); value.Host != hostname {
+		t.Errorf("wrong value for Host: %q", value.Host)
+	}
+
+	if value.Workers != runtime.NumCPU() {
+		t.Errorf("wrong value for Workers: %d", value.Workers)
+	}
+
+	if value.Procs != uint(runtime.GOMAXPROCS(0)) {
+		t.Errorf("wrong value for Procs: %d", value.Procs)
+	}
+
+	if len(value.Token) != 16 {
+		t.Errorf("wrong value for Token: %q", value.Token)
+	}
+
+	if secret, err := base64.StdEncoding.DecodeString(value.Secret); err != nil || len(secret) != 16 {
+		t.Errorf("wrong value for Secret: %q", value.Secret)
+	}
+
+	if wd, _ := os.Getwd(); value.Dir != wd {
+		t.Errorf("wrong value for Dir: %q", value.Dir)
+	}
+
+	if value.Custom != "name-of-Custom" {
+		t.Errorf("wrong value for Custom: %q", value.Custom)
+	}
+
+	if value.Handle != "@willow" {
+		t.Errorf("wrong value for Handle: %q", value.Handle)
+	}
+
+	var unknown struct {
+		A string `default:"@unknown"`
+	}
+
+	if err := parser.Set(&unknown); !errors.Is(err, defaults.ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+
+	if err := defaults.Set(&value); !errors.Is(err, defaults.ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider from the default parser, got %v", err)
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	t.Parallel()
+
+	defaults.RegisterProvider("testtoken", func(reflect.StructField) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte("token")), nil
+	})
+
+	var value struct {
+		Token string `default:"@testtoken"`
+	}
+
+	if err := defaults.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Token != "dG9rZW4=" {
+		t.Errorf("wrong value for Token: %q", value.Token)
+	}
+}
@@ -0,0 +1,71 @@
+package defaults
+
+import "reflect"
+
+type customParser func(value string) (reflect.Value, error)
+
+// RegisterParser registers a parser for values of type T in the default [Parser],
+// used by the package-level functions. Use [WithParser] to configure another [Parser].
+//
+// Registered parsers are consulted before the built-in parsers,
+// which makes it possible to support types that do not implement [encoding.TextUnmarshaler],
+// or to override how a type is parsed. Registering a parser for a type that already
+// has one replaces the previous parser.
+//
+// Since default values are parsed once for each struct type when possible (see [Compile]),
+// parsers should always return the same result for the same value.
+//
+// The methods generated by the defaultsgen command do not know about registered parsers:
+// types whose fields (or nested fields) have a registered parser are parsed with reflection,
+// even if they implement [GeneratedDefaulter].
+func RegisterParser[T any](parser func(string) (T, error)) {
+	defaultParser.mu.Lock()
+	defer defaultParser.mu.Unlock()
+
+	defaultParser.parsers[reflect.TypeFor[T]()] = wrapParser(parser)
+
+	// Plans and prototypes depend on the registered parsers.
+	defaultParser.plans.Clear()
+	defaultParser.prototypes.Clear()
+}
+
+// WithParser configures the parser to use the provided parser for values of type T.
+// See [RegisterParser] for details.
+func WithParser[T any](parser func(string) (T, error)) Option {
+	return func(p *Parser) {
+		p.parsers[reflect.TypeFor[T]()] = wrapParser(parser)
+
+		// Plans shared with another parser have been compiled without this parser.
+		p.plans = nil
+	}
+}
+
+func wrapParser[T any](parser func(string) (T, error)) customParser {
+	return func(value string) (reflect.Value, error) {
+		result, err := parser(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(&result).Elem(), nil
+	}
+}
+
+func (p *Parser) parseCustom(target reflect.Value, value string, hasDefault bool) (
+	result reflect.Value, hasParser bool, errs []error,
+) {
+	p.mu.RLock()
+	parser, hasParser := p.parsers[target.Type()]
+	p.mu.RUnlock()
+
+	if !hasParser || !hasDefault {
+		return reflect.Value{}, hasParser, nil
+	}
+
+	result, err := parser(value)
+	if err != nil {
+		return reflect.Value{}, true, []error{err}
+	}
+
+	return result, true, nil
+}
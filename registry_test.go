@@ -0,0 +1,57 @@
+package defaults_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type cents int64
+
+var errNotAnAmount = errors.New("not an amount")
+
+func parseCents(value string) (cents, error) {
+	units, decimals, ok := strings.Cut(value, ".")
+	if !ok || len(decimals) != 2 {
+		return 0, errNotAnAmount
+	}
+
+	amount, err := strconv.ParseInt(units+decimals, 10, 64)
+
+	return cents(amount), err
+}
+
+func TestRegisterParser(t *testing.T) {
+	t.Parallel()
+
+	// The parser is scoped: registering it globally would affect every other test.
+	parser := defaults.NewParser(defaults.WithParser(parseCents))
+
+	var value struct {
+		Price  cents   `default:"12.34"`
+		Prices []cents `default:"1.00,0.50"`
+	}
+
+	if err := parser.Set(&value); err != nil {
+		t.Fatalf("failed to apply defaults: %s", err)
+	}
+
+	if value.Price != 1234 {
+		t.Errorf("wrong value for Price: %d", value.Price)
+	}
+
+	if len(value.Prices) != 2 || value.Prices[0] != 100 || value.Prices[1] != 50 {
+		t.Errorf("wrong value for Prices: %v", value.Prices)
+	}
+
+	var invalid struct {
+		Price cents `default:"12"`
+	}
+
+	if err := parser.Set(&invalid); !errors.Is(err, errNotAnAmount) {
+		t.Errorf("expected errNotAnAmount, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package defaults
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is the format of the documentation rendered by [RenderDocs].
+type Format int
+
+const (
+	// FormatMarkdown renders a Markdown table.
+	FormatMarkdown Format = iota
+
+	// FormatText renders aligned plain text columns.
+	FormatText
+)
+
+// RenderDocs writes the documentation of the fields of typ, which must be a struct type
+// or a pointer to a struct type, to w: their path, type, raw default value and description,
+// from the "doc" tag. Fields are listed like [DescribeType] lists them.
+func RenderDocs(w io.Writer, typ reflect.Type, format Format) error {
+	return defaultParser.RenderDocs(w, typ, format)
+}
+
+// RenderDocs writes the documentation of the fields of typ to w, see [RenderDocs] for details.
+func (p *Parser) RenderDocs(w io.Writer, typ reflect.Type, format Format) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return ErrMustBePointerToAStruct
+	}
+
+	infos := p.DescribeType(typ)
+
+	switch format {
+	case FormatMarkdown:
+		return renderMarkdown(w, infos)
+	case FormatText:
+		return renderText(w, infos)
+	default:
+		return fmt.Errorf("%w: unknown format %d", ErrInvalidFormat, format)
+	}
+}
+
+func renderMarkdown(w io.Writer, infos []FieldInfo) error {
+	var doc strings.Builder
+
+	doc.WriteString("| Path | Type | Default | Description |\n")
+	doc.WriteString("| ---- | ---- | ------- | ----------- |\n")
+
+	for _, info := range infos {
+		fmt.Fprintf(
+			&doc, "| %s | %s | %s | %s |\n",
+			markdownCode(info.Path), markdownCode(info.Type.String()),
+			markdownCode(info.Default), markdownText(info.Doc),
+		)
+	}
+
+	_, err := io.WriteString(w, doc.String())
+
+	return err
+}
+
+// markdownCode formats value as inline code in a table cell.
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	delimiter := "`"
+	for strings.Contains(value, delimiter) {
+		delimiter += "`"
+	}
+
+	value = strings.ReplaceAll(value, "|", `\|`)
+
+	if strings.HasPrefix(value, "`") || strings.HasSuffix(value, "`") {
+		value = " " + value + " "
+	}
+
+	return delimiter + value + delimiter
+}
+
+// markdownText escapes value for a table cell.
+func markdownText(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+func renderText(w io.Writer, infos []FieldInfo) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd // Columns are separated by two spaces.
+
+	if _, err := fmt.Fprintln(writer, "PATH\tTYPE\tDEFAULT\tDESCRIPTION"); err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if _, err := fmt.Fprintf(
+			writer, "%s\t%s\t%s\t%s\n",
+			textCell(info.Path), textCell(info.Type.String()), textCell(info.Default), textCell(info.Doc),
+		); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// textCell replaces the tabs and line breaks of value with spaces, so that it fits in a column.
+func textCell(value string) string {
+	return strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ").Replace(value)
+}
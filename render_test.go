@@ -0,0 +1,91 @@
+package defaults_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type renderedBackend struct {
+	Host string `default:"localhost" doc:"Host | name"`
+}
+
+type renderedConfig struct {
+	Name    string        `default:"app" doc:"Application name"`
+	Timeout time.Duration `default:"5s"`
+	Backend renderedBackend
+}
+
+func TestRenderDocs(t *testing.T) {
+	t.Parallel()
+
+	var markdown strings.Builder
+	if err := defaults.RenderDocs(&markdown, reflect.TypeFor[renderedConfig](), defaults.FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMarkdown := "| Path | Type | Default | Description |\n" +
+		"| ---- | ---- | ------- | ----------- |\n" +
+		"| `Name` | `string` | `app` | Application name |\n" +
+		"| `Timeout` | `time.Duration` | `5s` |  |\n" +
+		"| `Backend` | `defaults_test.renderedBackend` |  |  |\n" +
+		"| `Backend.Host` | `string` | `localhost` | Host \\| name |\n"
+
+	if markdown.String() != expectedMarkdown {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectedMarkdown, markdown.String())
+	}
+
+	var text strings.Builder
+	if err := defaults.RenderDocs(&text, reflect.TypeFor[*renderedConfig](), defaults.FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedText := "PATH          TYPE                           DEFAULT    DESCRIPTION\n" +
+		"Name          string                         app        Application name\n" +
+		"Timeout       time.Duration                  5s         \n" +
+		"Backend       defaults_test.renderedBackend             \n" +
+		"Backend.Host  string                         localhost  Host | name\n"
+
+	if text.String() != expectedText {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectedText, text.String())
+	}
+
+	if err := defaults.RenderDocs(&text, reflect.TypeFor[int](), defaults.FormatText); err == nil {
+		t.Error("expected an error for a non-struct type")
+	}
+}
+
+type renderedSeparators struct {
+	Separator string `default:"a\tb" doc:"Column\tseparator\non two lines"`
+}
+
+type failingWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestRenderDocsText(t *testing.T) {
+	t.Parallel()
+
+	var text strings.Builder
+	if err := defaults.RenderDocs(&text, reflect.TypeFor[renderedSeparators](), defaults.FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tabs and line breaks in cells do not break the columns.
+	expectedText := "PATH       TYPE    DEFAULT  DESCRIPTION\n" +
+		"Separator  string  a b      Column separator on two lines\n"
+
+	if text.String() != expectedText {
+		t.Errorf("expected:\n%q\ngot:\n%q", expectedText, text.String())
+	}
+
+	if err := defaults.RenderDocs(failingWriter{}, reflect.TypeFor[renderedSeparators](), defaults.FormatText); !errors.Is(err, errWrite) {
+		t.Errorf("expected errWrite, got %v", err)
+	}
+}
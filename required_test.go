@@ -0,0 +1,47 @@
+package defaults_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/willoma/defaults"
+)
+
+type requiredDatabase struct {
+	DSN  string `required:""`
+	Pool int    `default:"4" required:"true"`
+}
+
+func TestRequired(t *testing.T) {
+	t.Parallel()
+
+	var value struct {
+		APIKey   string `required:""`
+		Name     string `default:"app" required:""`
+		Database requiredDatabase
+		Optional string `required:"false"`
+	}
+
+	err := defaults.Complete(&value)
+	if !errors.Is(err, defaults.ErrRequired) {
+		t.Fatalf("expected ErrRequired, got %v", err)
+	}
+
+	expected := "Database.DSN: value is required\nAPIKey: value is required"
+	if err.Error() != expected {
+		t.Errorf("wrong errors:\n%s\nexpected:\n%s", err, expected)
+	}
+
+	value.APIKey = "key"
+	value.Database.DSN = "postgres://localhost"
+
+	if err := defaults.Complete(&value); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	value.APIKey = ""
+	if err := defaults.Validate(&value); err == nil || !strings.HasPrefix(err.Error(), "APIKey: ") {
+		t.Errorf("expected APIKey to be reported by Validate, got %v", err)
+	}
+}
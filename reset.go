@@ -0,0 +1,181 @@
+package defaults
+
+import (
+	"reflect"
+)
+
+// Reset sets target, which must be a pointer to a struct, to its zero value,
+// then applies the tagged defaults, like [Set] would on a new value.
+// It is meant for objects recycled with a [sync.Pool].
+//
+// The fully defaulted value of each struct type is computed once, then copied into each target:
+// values without references cost a single copy and no allocation, and slices, maps, pointers
+// and math/big values are deep-copied, channels being created again with the same buffer size,
+// so that targets never share mutable state.
+//
+// Types whose defaults depend on the context (conditions, providers, references,
+// environment variables or relative times), or whose structs have hooks or required fields,
+// are reset to their zero value then defaulted with [Set] instead.
+func Reset(target any) error { return defaultParser.Reset(target) }
+
+// Reset resets target and applies the defaults, see [Reset] for details.
+func (p *Parser) Reset(target any) error {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return ErrMustBePointerToAStruct
+	}
+
+	elem := val.Elem()
+	if elem.Kind() != reflect.Struct {
+		return ErrMustBePointerToAStruct
+	}
+
+	proto := p.prototype(elem.Type())
+	if !proto.value.IsValid() {
+		elem.SetZero()
+
+		return p.apply(target, true)
+	}
+
+	elem.Set(proto.value)
+
+	for _, path := range proto.references {
+		elem.FieldByIndex(path).Set(cloneValue(proto.value.FieldByIndex(path)))
+	}
+
+	return nil
+}
+
+// prototype is the fully defaulted value of a struct type.
+type prototype struct {
+	// value is invalid if the type cannot be reset by copying a prototype.
+	value reflect.Value
+
+	// references holds the paths to the fields of value that must be deep-copied.
+	references [][]int
+}
+
+// prototype returns the prototype of typ, which is computed on first use and cached by the parser.
+func (p *Parser) prototype(typ reflect.Type) *prototype {
+	if cached, ok := p.prototypes.Load(typ); ok {
+		proto, _ := cached.(*prototype)
+
+		return proto
+	}
+
+	proto := &prototype{}
+
+	app := &applier{Parser: p}
+	if app.canPrototype(typ, map[reflect.Type]bool{}) {
+		value := reflect.New(typ).Elem()
+		if _, errs := app.parseStruct(value, true); len(errs) == 0 {
+			proto.value = value
+			proto.references = referencePaths(value, nil)
+		}
+	}
+
+	if !p.noPlanCache {
+		p.prototypes.Store(typ, proto)
+	}
+
+	return proto
+}
+
+// canPrototype reports whether the defaults of typ are the same for every value,
+// in which case a prototype may be copied instead of applying them.
+func (a *applier) canPrototype(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return true
+	}
+
+	visited[typ] = true
+
+	pointer := reflect.PointerTo(typ)
+	if pointer.Implements(reflect.TypeFor[AfterDefaulter]()) ||
+		pointer.Implements(reflect.TypeFor[FallibleDefaulter]()) ||
+		(pointer.Implements(reflect.TypeFor[Defaulter]()) && !pointer.Implements(reflect.TypeFor[GeneratedDefaulter]())) {
+		return false
+	}
+
+	plan := a.plan(typ)
+	if plan.err != nil {
+		return false
+	}
+
+	for _, field := range plan.fields {
+		if field.static {
+			continue
+		}
+
+		if _, ok := a.lookupDefault(field.typeField.Tag); ok {
+			return false
+		}
+
+		for _, name := range []string{conditionalTagName, jsonTagName, "required"} {
+			if _, ok := field.typeField.Tag.Lookup(name); ok {
+				return false
+			}
+		}
+
+		if !a.canPrototypeNested(field.typeField, visited) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// canPrototypeNested reports whether the nested struct of typeField, if any, can be copied from a prototype.
+func (a *applier) canPrototypeNested(typeField reflect.StructField, visited map[reflect.Type]bool) bool {
+	typ := typeField.Type
+
+	if typ.Kind() == reflect.Pointer {
+		mode, err := a.fieldPointerMode(typeField)
+		if err != nil {
+			return false
+		}
+
+		if mode != PointerAllocate {
+			// Nil pointers stay nil in the prototype.
+			return true
+		}
+
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || a.hasParser(typ) {
+		// Other values stay zero in the prototype.
+		return true
+	}
+
+	return a.canPrototype(typ, visited)
+}
+
+// referencePaths returns the paths to the fields of value that reference mutable state,
+// looking into nested structs.
+func referencePaths(value reflect.Value, prefix []int) [][]int {
+	var paths [][]int
+
+	for i := range value.NumField() {
+		field := value.Field(i)
+		if !value.Type().Field(i).IsExported() || field.IsZero() || isScalar(field.Type()) {
+			continue
+		}
+
+		path := append(append([]int{}, prefix...), i)
+
+		if field.Kind() == reflect.Struct && structCloners[field.Type()] == nil {
+			paths = append(paths, referencePaths(field, path)...)
+
+			continue
+		}
+
+		if field.Kind() == reflect.Array && isScalar(field.Type().Elem()) {
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
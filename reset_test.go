@@ -0,0 +1,126 @@
+package defaults_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type resetBackend struct {
+	Host string `default:"localhost"`
+	Port int    `default:"80"`
+}
+
+type resetConfig struct {
+	Name     string         `default:"app"`
+	Timeout  time.Duration  `default:"5s"`
+	Hosts    []string       `default:"a,b"`
+	Limits   map[string]int `default:"read:10"`
+	Total    *big.Int       `default:"12345678901234567890"`
+	Events   chan string    `default:"4"`
+	Backend  resetBackend
+	Fallback *resetBackend `default_ptr:"allocate"`
+	Servers  []resetBackend
+	buffer   []byte
+}
+
+type scalarConfig struct {
+	Name    string        `default:"app"`
+	Port    int           `default:"8080"`
+	Timeout time.Duration `default:"5s"`
+	Start   time.Time     `default:"2024-01-02"`
+}
+
+type dynamicConfig struct {
+	Name string `default:"app"`
+	URL  string `default:"http://${Name}"`
+}
+
+//nolint:paralleltest // AllocsPerRun cannot run in parallel tests.
+func TestReset(t *testing.T) {
+	var first, second resetConfig
+	if err := defaults.Reset(&first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := defaults.Reset(&second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Name != "app" || first.Hosts[1] != "b" || first.Total.String() != "12345678901234567890" ||
+		cap(first.Events) != 4 || first.Backend.Port != 80 || first.Fallback.Host != "localhost" {
+		t.Errorf("unexpected values: %+v", first)
+	}
+
+	// Targets do not share mutable state.
+	first.Hosts[0] = "changed"
+	first.Limits["read"] = 0
+	first.Total.SetInt64(0)
+	first.Fallback.Port = 0
+
+	if second.Hosts[0] != "a" || second.Limits["read"] != 10 || second.Total.String() != "12345678901234567890" ||
+		second.Fallback.Port != 80 || first.Events == second.Events {
+		t.Errorf("values are shared between targets: %+v", second)
+	}
+
+	// Recycled objects lose their previous values.
+	first.Servers = []resetBackend{{}}
+	first.buffer = []byte("data")
+
+	if err := defaults.Reset(&first); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Hosts[0] != "a" || first.Servers != nil || first.buffer != nil {
+		t.Errorf("unexpected values after reset: %+v", first)
+	}
+
+	// Types whose defaults depend on the context are reset, then defaulted.
+	dynamic := dynamicConfig{Name: "custom", URL: "http://example.com"}
+	if err := defaults.Reset(&dynamic); err != nil {
+		t.Fatal(err)
+	}
+
+	if dynamic.URL != "http://app" {
+		t.Errorf("unexpected URL: %q", dynamic.URL)
+	}
+
+	// Resetting values without references does not allocate.
+	var scalar scalarConfig
+	if allocs := testing.AllocsPerRun(100, func() { _ = defaults.Reset(&scalar) }); allocs != 0 {
+		t.Errorf("expected no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkReset(b *testing.B) {
+	b.Run("set", func(b *testing.B) {
+		for range b.N {
+			var config benchmarkConfig
+			if err := defaults.Set(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("reset", func(b *testing.B) {
+		var config benchmarkConfig
+
+		for range b.N {
+			if err := defaults.Reset(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("scalar", func(b *testing.B) {
+		var config scalarConfig
+
+		for range b.N {
+			if err := defaults.Reset(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,516 @@
+package defaults
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of T, which must be a struct type
+// or a pointer to a struct type. See [JSONSchemaType] for details.
+func JSONSchema[T any]() ([]byte, error) { return JSONSchemaType(reflect.TypeFor[T]()) }
+
+// JSONSchemaType returns the JSON Schema (draft 2020-12) of typ, which must be a struct type
+// or a pointer to a struct type, describing its JSON encoding with [encoding/json],
+// except for the types written as strings like in tags, listed below.
+//
+// Property names honour the "json" struct tags, and the fields of embedded structs are promoted.
+// The "default" keyword is filled with the parsed default values, [time.Duration] values being
+// serialized as strings like "1m30s", [fs.FileMode] values as octal strings like "0644"
+// and [net.HardwareAddr] values as strings like "00:00:5e:00:53:01", as they are written in tags,
+// which are also the types of their schemas. Dynamic defaults (see [FieldInfo]) are omitted.
+// The "description" keyword is filled from the "doc" tag.
+//
+// Validation tags are mapped to the matching keywords: min and max to minimum and maximum
+// for numbers, or to minLength, minItems or minProperties (and their max counterparts)
+// for values with a length; len to both bounds of the length; oneof to enum; pattern to pattern
+// for strings; and fields with the "required" tag are listed in the "required" keyword.
+// Since JSON Schema has no bounds for strings, the bounds of durations and file modes
+// are written in the same form as their values, in the "x-minimum" and "x-maximum" annotations.
+//
+// Recursive struct types are defined in "$defs", named after their package path and name
+// (for instance "example.com.app.Node"), and referenced with "$ref".
+// Channels, functions and complex numbers, which cannot be encoded in JSON, are omitted.
+// Errors in default values and validation tags are returned, joined, prefixed with the path of the field.
+func JSONSchemaType(typ reflect.Type) ([]byte, error) { return defaultParser.JSONSchemaType(typ) }
+
+// JSONSchemaType returns the JSON Schema of typ, see [JSONSchemaType] for details.
+func (p *Parser) JSONSchemaType(typ reflect.Type) ([]byte, error) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, ErrMustBePointerToAStruct
+	}
+
+	builder := &schemaBuilder{
+		applier:   &applier{Parser: p},
+		root:      typ,
+		visiting:  map[reflect.Type]bool{},
+		recursive: map[reflect.Type]bool{},
+		defs:      map[string]any{},
+	}
+
+	schema := builder.structSchema(typ, "")
+	if len(builder.errs) > 0 {
+		return nil, errors.Join(builder.errs...)
+	}
+
+	schema["$schema"] = jsonSchemaDialect
+
+	if typ.Name() != "" {
+		schema["title"] = typ.Name()
+	}
+
+	if len(builder.defs) > 0 {
+		schema["$defs"] = builder.defs
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type schemaBuilder struct {
+	*applier
+
+	root reflect.Type
+
+	// visiting holds the struct types being described, to detect recursive types.
+	visiting map[reflect.Type]bool
+
+	// recursive holds the struct types that reference themselves, which are defined in defs.
+	recursive map[reflect.Type]bool
+
+	defs map[string]any
+	errs []error
+}
+
+// typeSchema returns the schema of typ, or nil if values of typ cannot be encoded in JSON.
+//
+//nolint:cyclop // This is a flat mapping of types.
+func (b *schemaBuilder) typeSchema(typ reflect.Type, path string) map[string]any {
+	switch typ {
+	case reflect.TypeFor[time.Duration](), reflect.TypeFor[fs.FileMode](), reflect.TypeFor[net.HardwareAddr]():
+		return map[string]any{"type": "string"}
+	case reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	if implements[json.Marshaler](typ) {
+		// The JSON encoding is only known at runtime.
+		return map[string]any{}
+	}
+
+	if implements[encoding.TextMarshaler](typ) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Pointer:
+		return b.typeSchema(typ.Elem(), path)
+	case reflect.Array, reflect.Slice:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+
+		items := b.typeSchema(typ.Elem(), path+".*")
+		if items == nil {
+			return nil
+		}
+
+		schema := map[string]any{"type": "array", "items": items}
+		if typ.Kind() == reflect.Array {
+			schema["minItems"] = typ.Len()
+			schema["maxItems"] = typ.Len()
+		}
+
+		return schema
+	case reflect.Map:
+		values := b.typeSchema(typ.Elem(), path+".*")
+		if values == nil {
+			return nil
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": values}
+	case reflect.Struct:
+		return b.structSchema(typ, path)
+	default:
+		return nil
+	}
+}
+
+// structSchema returns the schema of typ, or a reference to its definition if it is recursive.
+func (b *schemaBuilder) structSchema(typ reflect.Type, path string) map[string]any {
+	if b.visiting[typ] {
+		b.recursive[typ] = true
+
+		return map[string]any{"$ref": b.ref(typ)}
+	}
+
+	b.visiting[typ] = true
+	defer delete(b.visiting, typ)
+
+	properties, required := b.properties(typ, path)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	if b.recursive[typ] && typ != b.root {
+		b.defs[defName(typ)] = schema
+
+		return map[string]any{"$ref": b.ref(typ)}
+	}
+
+	return schema
+}
+
+func (b *schemaBuilder) ref(typ reflect.Type) string {
+	if typ == b.root {
+		return "#"
+	}
+
+	return "#/$defs/" + defName(typ)
+}
+
+// defName returns the name of the definition of typ in "$defs", qualified by its package path
+// so that types with the same name in different packages do not collide.
+// Slashes are replaced with dots, so that the name needs no escaping in references.
+func defName(typ reflect.Type) string {
+	if typ.PkgPath() == "" {
+		return strings.ReplaceAll(typ.Name(), "/", ".")
+	}
+
+	return strings.ReplaceAll(typ.PkgPath()+"."+typ.Name(), "/", ".")
+}
+
+// properties returns the schemas of the fields of typ and the names of its required fields,
+// including the fields of embedded structs without a JSON name, like [encoding/json] does:
+// these promoted fields are hidden by the fields of typ with the same name.
+func (b *schemaBuilder) properties(typ reflect.Type, path string) (map[string]any, []string) {
+	properties := map[string]any{}
+	promoted := map[string]any{}
+
+	var required, promotedRequired []string
+
+	for i := range typ.NumField() {
+		typeField := typ.Field(i)
+
+		name, tagged, skip := jsonName(typeField)
+		if skip {
+			continue
+		}
+
+		fieldPath := typeField.Name
+		if path != "" {
+			fieldPath = path + "." + typeField.Name
+		}
+
+		if embedded := typeField.Type; typeField.Anonymous && !tagged {
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				embeddedProperties, embeddedRequired := b.properties(embedded, fieldPath)
+				maps.Copy(promoted, embeddedProperties)
+				promotedRequired = append(promotedRequired, embeddedRequired...)
+
+				continue
+			}
+		}
+
+		if !typeField.IsExported() {
+			continue
+		}
+
+		schema := b.typeSchema(typeField.Type, fieldPath)
+		if schema == nil {
+			continue
+		}
+
+		b.annotate(schema, typeField, fieldPath)
+		properties[name] = schema
+
+		if tag, ok := typeField.Tag.Lookup("required"); ok {
+			isRequired, err := parseFlag(tag)
+			if err != nil {
+				b.errs = append(b.errs, addErrorsPrefixes(fieldPath, []error{err})...)
+			} else if isRequired {
+				required = append(required, name)
+			}
+		}
+	}
+
+	for _, name := range promotedRequired {
+		if _, hidden := properties[name]; !hidden {
+			required = append(required, name)
+		}
+	}
+
+	for name, schema := range promoted {
+		if _, hidden := properties[name]; !hidden {
+			properties[name] = schema
+		}
+	}
+
+	return properties, required
+}
+
+// annotate adds the description, the default value and the validation keywords of typeField to schema.
+func (b *schemaBuilder) annotate(schema map[string]any, typeField reflect.StructField, path string) {
+	info := b.describeField(typeField)
+
+	if info.Doc != "" {
+		schema["description"] = info.Doc
+	}
+
+	if info.Err != nil {
+		b.errs = append(b.errs, addErrorsPrefixes(path, []error{info.Err})...)
+	} else if info.Value != nil {
+		schema["default"] = jsonValue(reflect.ValueOf(info.Value))
+	}
+
+	if err := b.addValidation(schema, typeField); err != nil {
+		b.errs = append(b.errs, addErrorsPrefixes(path, []error{err})...)
+	}
+}
+
+// addValidation maps the validation tags of typeField to JSON Schema keywords.
+// Bounds on durations and file modes are written as annotations, in the same form as their values,
+// and bounds on other types that are encoded as strings, like [time.Time], are ignored.
+func (b *schemaBuilder) addValidation(schema map[string]any, typeField reflect.StructField) error {
+	typ := typeField.Type
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	for _, bound := range []struct{ tag, prefix string }{{"min", "min"}, {"max", "max"}, {"len", "min"}, {"len", "max"}} {
+		value, ok := typeField.Tag.Lookup(bound.tag)
+		if !ok {
+			continue
+		}
+
+		keyword, isLength := schemaBoundKeyword(typ, schema["type"], bound.prefix)
+
+		switch {
+		case keyword == "":
+		case isLength:
+			length, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			schema[keyword] = length
+		case bound.tag != "len":
+			limit, errs := b.parse(reflect.New(typ).Elem(), value, true, true)
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+
+			schema[keyword] = jsonValue(limit)
+		}
+	}
+
+	if oneOf, ok := typeField.Tag.Lookup("oneof"); ok {
+		candidates := asList(oneOf)
+		enum := make([]any, 0, len(candidates))
+
+		for _, candidate := range candidates {
+			value, errs := b.parse(reflect.New(typ).Elem(), candidate, true, true)
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+
+			enum = append(enum, jsonValue(value))
+		}
+
+		schema["enum"] = enum
+	}
+
+	if pattern, ok := typeField.Tag.Lookup("pattern"); ok && typ.Kind() == reflect.String && schema["type"] == "string" {
+		schema["pattern"] = pattern
+	}
+
+	return nil
+}
+
+// schemaBoundKeyword returns the keyword for the min or max bound (according to prefix) of values of typ,
+// described with the schemaType JSON type, and reports whether the bound is a length.
+// It returns an empty keyword if the bound cannot be expressed.
+func schemaBoundKeyword(typ reflect.Type, schemaType any, prefix string) (string, bool) {
+	switch {
+	case typ == reflect.TypeFor[time.Duration]() || typ == reflect.TypeFor[fs.FileMode]():
+		return "x-" + prefix + "imum", false
+	case typ.Kind() == reflect.String && schemaType == "string":
+		return prefix + "Length", true
+	case (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && schemaType == "array":
+		return prefix + "Items", true
+	case typ.Kind() == reflect.Map && schemaType == "object":
+		return prefix + "Properties", true
+	case schemaType == "integer" || schemaType == "number":
+		return prefix + "imum", false
+	default:
+		return "", false
+	}
+}
+
+// jsonValue converts value to a value encoded by [encoding/json] like the schema describes it.
+//
+//nolint:cyclop // This is a flat mapping of types.
+func jsonValue(value reflect.Value) any {
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Type() {
+	case reflect.TypeFor[time.Duration]():
+		return time.Duration(value.Int()).String()
+	case reflect.TypeFor[fs.FileMode]():
+		return fmt.Sprintf("%#o", value.Uint())
+	case reflect.TypeFor[net.HardwareAddr]():
+		return net.HardwareAddr(value.Bytes()).String()
+	}
+
+	if marshaler, ok := as[json.Marshaler](value); ok {
+		if data, err := marshaler.MarshalJSON(); err == nil {
+			return json.RawMessage(data)
+		}
+	}
+
+	if marshaler, ok := as[encoding.TextMarshaler](value); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return jsonValue(value.Elem())
+
+	case reflect.Array, reflect.Slice:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Bytes()
+		}
+
+		items := make([]any, 0, value.Len())
+		for i := range value.Len() {
+			items = append(items, jsonValue(value.Index(i)))
+		}
+
+		return items
+
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+
+		object := make(map[string]any, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			object[formatValue(iter.Key())] = jsonValue(iter.Value())
+		}
+
+		return object
+
+	case reflect.Struct:
+		object := map[string]any{}
+		addJSONFields(value, object)
+
+		return object
+
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return nil
+
+	default:
+		return value.Interface()
+	}
+}
+
+// addJSONFields adds the fields of value, a struct, to object, promoting the fields of embedded structs.
+func addJSONFields(value reflect.Value, object map[string]any) {
+	for i := range value.NumField() {
+		typeField := value.Type().Field(i)
+
+		name, tagged, skip := jsonName(typeField)
+		if skip {
+			continue
+		}
+
+		field := value.Field(i)
+
+		if typeField.Anonymous && !tagged {
+			if field.Kind() == reflect.Pointer && !field.IsNil() {
+				field = field.Elem()
+			}
+
+			if field.Kind() == reflect.Struct {
+				addJSONFields(field, object)
+
+				continue
+			}
+		}
+
+		if typeField.IsExported() {
+			object[name] = jsonValue(field)
+		}
+	}
+}
+
+// implements reports whether values of typ, or pointers to them, implement I.
+func implements[I any](typ reflect.Type) bool {
+	iface := reflect.TypeFor[I]()
+
+	return typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface)
+}
+
+// as returns value as an I, if value or a pointer to a copy of value implements I.
+func as[I any](value reflect.Value) (I, bool) {
+	if !value.CanInterface() {
+		var zero I
+
+		return zero, false
+	}
+
+	if result, ok := value.Interface().(I); ok {
+		return result, true
+	}
+
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	result, ok := pointer.Interface().(I)
+
+	return result, ok
+}
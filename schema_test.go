@@ -0,0 +1,112 @@
+package defaults_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type schemaBackend struct {
+	Name string `default:"backend"   json:"name"`
+	Host string `default:"localhost" doc:"Host name" json:"host" pattern:"^[a-z.]+$"`
+	Port uint16 `default:"8080"      json:"port"     max:"9000"  min:"1024"`
+}
+
+type schemaNode struct {
+	Name     string        `json:"name"`
+	Children []*schemaNode `json:"children"`
+}
+
+type schemaConfig struct {
+	schemaBackend
+
+	Name    string           `default:"app"            json:"name"    required:""`
+	Level   string           `default:"info"           json:"level"   oneof:"debug,info,warn"`
+	Timeout time.Duration    `default:"5s"             json:"timeout" min:"1s"`
+	Mode    fs.FileMode      `default:"0644"           json:"mode"    max:"0777"`
+	MAC     net.HardwareAddr `default:"00:00:5e:00:53:01" json:"mac"`
+	Tags    []string         `default:"a,b"            json:"tags"    max:"5"`
+	URL     string           `default:"http://${Name}" json:"url"`
+	Tree    schemaNode       `json:"tree"`
+	Next    *schemaConfig    `json:"next,omitempty"`
+	Skipped string           `json:"-"`
+	Notify  func()
+}
+
+type invalidSchemaConfig struct {
+	Count int `default:"abc"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := defaults.JSONSchema[schemaConfig]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if schema["$schema"] != "https://json-schema.org/draft/2020-12/schema" || schema["title"] != "schemaConfig" {
+		t.Errorf("unexpected schema header: %s", data)
+	}
+
+	if required := schema["required"]; !reflect.DeepEqual(required, []any{"name"}) {
+		t.Errorf("expected required [name], got %v", required)
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+
+	if len(properties) != 11 {
+		t.Errorf("expected 11 properties, got %v", names)
+	}
+
+	expected := map[string]map[string]any{
+		"name":    {"type": "string", "default": "app"},
+		"host":    {"type": "string", "default": "localhost", "description": "Host name", "pattern": "^[a-z.]+$"},
+		"port":    {"type": "integer", "default": 8080.0, "minimum": 1024.0, "maximum": 9000.0},
+		"level":   {"type": "string", "default": "info", "enum": []any{"debug", "info", "warn"}},
+		"timeout": {"type": "string", "default": "5s", "x-minimum": "1s"},
+		"mode":    {"type": "string", "default": "0644", "x-maximum": "0777"},
+		"mac":     {"type": "string", "default": "00:00:5e:00:53:01"},
+		"tags":    {"type": "array", "items": map[string]any{"type": "string"}, "default": []any{"a", "b"}, "maxItems": 5.0},
+		"url":     {"type": "string"},
+		"tree":    {"$ref": "#/$defs/github.com.willoma.defaults_test.schemaNode"},
+		"next":    {"$ref": "#"},
+	}
+
+	for name, expectedProperty := range expected {
+		if property := properties[name]; !reflect.DeepEqual(property, any(expectedProperty)) {
+			t.Errorf("expected property %s to be %v, got %v", name, expectedProperty, property)
+		}
+	}
+
+	node, _ := schema["$defs"].(map[string]any)["github.com.willoma.defaults_test.schemaNode"].(map[string]any)
+	if children := node["properties"].(map[string]any)["children"]; !reflect.DeepEqual(children, map[string]any{
+		"type": "array", "items": map[string]any{"$ref": "#/$defs/github.com.willoma.defaults_test.schemaNode"},
+	}) {
+		t.Errorf("unexpected recursive definition: %v", node)
+	}
+
+	if _, err := defaults.JSONSchema[invalidSchemaConfig](); err == nil {
+		t.Error("expected an error for an invalid default")
+	}
+
+	if _, err := defaults.JSONSchema[int](); !errors.Is(err, defaults.ErrMustBePointerToAStruct) {
+		t.Errorf("expected ErrMustBePointerToAStruct, got %v", err)
+	}
+}
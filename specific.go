@@ -9,7 +9,7 @@ import (
 	"time"
 )
 
-func parseSpecific(
+func (a *applier) parseSpecific(
 	target reflect.Value, value string, hasDefault bool,
 ) (result reflect.Value, hasParser bool, errs []error) {
 	switch target.Type() {
@@ -52,7 +52,7 @@ func parseSpecific(
 		if hasDefault {
 			var stamp time.Time
 
-			stamp, errs = parseTime(value)
+			stamp, errs = a.parseTime(value)
 			result = reflect.ValueOf(stamp)
 		}
 	}
@@ -138,8 +138,18 @@ func parseFileModeRwxrwxrwx(value string) fs.FileMode {
 //   - [time.TimeOnly] ("HH:MM:SS")
 //   - "HH:MM"
 //
+// It also supports times relative to the parser clock, see parseRelativeTime.
+//
 // If none of the formats match, all parsing errors are joined and returned.
-func parseTime(value string) (time.Time, []error) {
+func (a *applier) parseTime(value string) (time.Time, []error) {
+	if parsed, isRelative, err := a.parseRelativeTime(value); isRelative {
+		if err != nil {
+			return time.Time{}, []error{err}
+		}
+
+		return parsed, nil
+	}
+
 	parsed, err := time.Parse(time.RFC3339, value)
 	if err == nil {
 		return parsed, nil
@@ -0,0 +1,225 @@
+package defaults
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// validateStruct checks the exported fields of target against their validation tags,
+// and walks nested structs, pointers and collections like when applying defaults.
+func (a *applier) validateStruct(target reflect.Value) []error {
+	var errs []error
+
+	for i := range target.NumField() {
+		typeField := target.Type().Field(i)
+		if !typeField.IsExported() {
+			continue
+		}
+
+		field := target.Field(i)
+
+		fieldErrs := a.parseElement(field, false)
+		fieldErrs = append(fieldErrs, a.validateField(field, typeField)...)
+
+		if len(fieldErrs) > 0 {
+			errs = append(errs, addErrorsPrefixes(typeField.Name, fieldErrs)...)
+		}
+	}
+
+	return errs
+}
+
+// validateField checks the value of field against the validation tags of typeField.
+func (a *applier) validateField(field reflect.Value, typeField reflect.StructField) []error {
+	var errs []error
+
+	if err := checkRequired(field, typeField); err != nil {
+		errs = append(errs, err)
+	}
+
+	if tag, ok := typeField.Tag.Lookup("nonzero"); ok {
+		if enabled, err := parseFlag(tag); err != nil {
+			errs = append(errs, err)
+		} else if enabled && field.IsZero() {
+			errs = append(errs, fmt.Errorf("%w: must not be zero", ErrInvalidValue))
+		}
+	}
+
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return errs
+		}
+
+		field = field.Elem()
+	}
+
+	for _, check := range []struct {
+		tag      string
+		validate func(field reflect.Value, tag string) error
+	}{
+		{"min", a.validateMin},
+		{"max", a.validateMax},
+		{"len", validateLen},
+		{"oneof", a.validateOneOf},
+		{"pattern", validatePattern},
+	} {
+		if tag, ok := typeField.Tag.Lookup(check.tag); ok {
+			if err := check.validate(field, tag); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (a *applier) validateMin(field reflect.Value, tag string) error {
+	cmp, current, err := a.compareBound(field, tag)
+	if err != nil {
+		return err
+	}
+
+	if cmp < 0 {
+		return fmt.Errorf("%w: %s is less than the minimum %s", ErrInvalidValue, current, tag)
+	}
+
+	return nil
+}
+
+func (a *applier) validateMax(field reflect.Value, tag string) error {
+	cmp, current, err := a.compareBound(field, tag)
+	if err != nil {
+		return err
+	}
+
+	if cmp > 0 {
+		return fmt.Errorf("%w: %s is greater than the maximum %s", ErrInvalidValue, current, tag)
+	}
+
+	return nil
+}
+
+// compareBound compares the value of field (or its length) with bound,
+// and returns the result of the comparison and a description of the compared value.
+func (a *applier) compareBound(field reflect.Value, bound string) (int, string, error) {
+	if length, hasLength := lengthOf(field); hasLength {
+		limit, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, "", err
+		}
+
+		return cmp.Compare(length, limit), "length " + strconv.Itoa(length), nil
+	}
+
+	limit, errs := a.parse(reflect.New(field.Type()).Elem(), bound, true, true)
+	if len(errs) > 0 {
+		return 0, "", errs[0]
+	}
+
+	current := formatValue(field)
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(field.Int(), limit.Int()), current, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(field.Uint(), limit.Uint()), current, nil
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(field.Float(), limit.Float()), current, nil
+	default:
+		return 0, "", fmt.Errorf("%w: min and max need numbers or values with a length", ErrUnsupportedType)
+	}
+}
+
+func validateLen(field reflect.Value, tag string) error {
+	expected, err := strconv.Atoi(tag)
+	if err != nil {
+		return err
+	}
+
+	length, hasLength := lengthOf(field)
+	if !hasLength {
+		return fmt.Errorf("%w: len needs values with a length", ErrUnsupportedType)
+	}
+
+	if length != expected {
+		return fmt.Errorf("%w: length %d is not %d", ErrInvalidValue, length, expected)
+	}
+
+	return nil
+}
+
+func (a *applier) validateOneOf(field reflect.Value, tag string) error {
+	current := formatValue(field)
+	allowed := asList(tag)
+
+	for _, candidate := range allowed {
+		parsed, errs := a.parse(reflect.New(field.Type()).Elem(), candidate, true, true)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+
+		if formatValue(parsed) == current {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q is not one of %q", ErrInvalidValue, current, allowed)
+}
+
+func validatePattern(field reflect.Value, tag string) error {
+	pattern, err := regexp.Compile(tag)
+	if err != nil {
+		return err
+	}
+
+	if current := formatValue(field); !pattern.MatchString(current) {
+		return fmt.Errorf("%w: %q does not match %q", ErrInvalidValue, current, tag)
+	}
+
+	return nil
+}
+
+// lengthOf returns the length of value, and reports whether values of its kind have a length.
+// The length of strings is their number of runes.
+func lengthOf(value reflect.Value) (int, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), true
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice:
+		return value.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+// checkRequired returns [ErrRequired] if typeField has the "required" tag and field is zero.
+func checkRequired(field reflect.Value, typeField reflect.StructField) error {
+	tag, ok := typeField.Tag.Lookup("required")
+	if !ok {
+		return nil
+	}
+
+	required, err := parseFlag(tag)
+	if err != nil {
+		return err
+	}
+
+	if required && field.IsZero() {
+		return ErrRequired
+	}
+
+	return nil
+}
+
+// parseFlag parses the value of a flag tag, an empty value meaning true.
+func parseFlag(tag string) (bool, error) {
+	if tag == "" {
+		return true, nil
+	}
+
+	return strconv.ParseBool(tag)
+}
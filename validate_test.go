@@ -0,0 +1,103 @@
+package defaults_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/willoma/defaults"
+)
+
+type validatedServer struct {
+	Host string `default:"localhost" pattern:"^[a-z.]+$"`
+	Port int    `default:"8080"      min:"1"          max:"65535"`
+}
+
+type validatedConfig struct {
+	Name     string        `default:"app"   len:"3"`
+	Level    string        `default:"info"  oneof:"debug,info,warn,error"`
+	Timeout  time.Duration `default:"30s"   min:"1s"                      max:"1m"`
+	Tags     []string      `default:"a,b"   min:"1"                       max:"3"`
+	Ratio    *float64      `default:"0.5"   min:"0"                       max:"1"`
+	Token    string        `nonzero:""`
+	Servers  []validatedServer
+	Primary  validatedServer
+	Optional *validatedServer
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	value := validatedConfig{Token: "secret", Servers: []validatedServer{{}}}
+	if err := defaults.CompleteAndValidate(&value); err != nil {
+		t.Fatalf("failed to apply and validate defaults: %s", err)
+	}
+
+	ratio := 1.5
+	value = validatedConfig{
+		Name:    "application",
+		Level:   "verbose",
+		Timeout: time.Hour,
+		Tags:    []string{},
+		Ratio:   &ratio,
+		Servers: []validatedServer{{Host: "Invalid Host", Port: 80}},
+		Primary: validatedServer{Host: "localhost", Port: 70000},
+	}
+
+	err := defaults.Validate(&value)
+	if !errors.Is(err, defaults.ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+
+	expected := []string{
+		"Name: invalid value: length 11 is not 3",
+		`Level: invalid value: "verbose" is not one of ["debug" "info" "warn" "error"]`,
+		"Timeout: invalid value: 1h0m0s is greater than the maximum 1m",
+		"Tags: invalid value: length 0 is less than the minimum 1",
+		"Ratio: invalid value: 1.5 is greater than the maximum 1",
+		"Token: invalid value: must not be zero",
+		`Servers.0.Host: invalid value: "Invalid Host" does not match "^[a-z.]+$"`,
+		"Primary.Port: invalid value: 70000 is greater than the maximum 65535",
+	}
+
+	if err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("wrong errors:\n%s\nexpected:\n%s", err, strings.Join(expected, "\n"))
+	}
+}
+
+func TestValidateMapsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	value := struct {
+		Servers map[string]validatedServer
+	}{
+		Servers: map[string]validatedServer{"main": {Host: "localhost", Port: 80}},
+	}
+
+	// Validating does not write to maps, so that they may be read concurrently (see go test -race).
+	var wg sync.WaitGroup
+
+	for range 4 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			if err := defaults.Validate(&value); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for _, server := range value.Servers {
+				_ = server.Port
+			}
+		}()
+	}
+
+	wg.Wait()
+}